@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/master"
 	"github.com/jackchuka/hpp/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +15,7 @@ import (
 var areaCmd = &cobra.Command{
 	Use:   "area",
 	Short: "List geographic areas",
-	Long:  "List large, middle, or small geographic areas.",
+	Long:  "List large, middle, or small geographic areas, or the whole hierarchy.",
 }
 
 // --- large area ---
@@ -32,13 +34,12 @@ var areaLargeCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.LargeAreaResponse
-		if err := client.Get("/large_area/v1/", largeAreaParams, &resp); err != nil {
+		if err := src.Get("/large_area/v1/", largeAreaParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -76,13 +77,12 @@ var areaMiddleCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.MiddleAreaResponse
-		if err := client.Get("/middle_area/v1/", middleAreaParams, &resp); err != nil {
+		if err := src.Get("/middle_area/v1/", middleAreaParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -120,13 +120,12 @@ var areaSmallCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.SmallAreaResponse
-		if err := client.Get("/small_area/v1/", smallAreaParams, &resp); err != nil {
+		if err := src.Get("/small_area/v1/", smallAreaParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -141,11 +140,93 @@ var areaSmallCmd = &cobra.Command{
 	},
 }
 
+// --- tree ---
+var areaTreeRoot string
+var areaTreeDepth int
+
+var areaTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "Show the area hierarchy as a tree",
+	Long: `Show the full area hierarchy: large service area, service area, large area,
+middle area and small area, with the number of areas below each node.`,
+	Example: `  hpp area tree --depth 3 --format table
+  hpp area tree --root Z011 --format table
+  hpp area tree --root 新橋・汐留 --offline`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var snap *master.Snapshot
+		if masterOffline {
+			var err error
+			if snap, err = latestSnapshot(); err != nil {
+				return err
+			}
+		} else {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			if snap, err = master.FetchAreas(client); err != nil {
+				return err
+			}
+		}
+
+		nodes := snap.AreaTree()
+		if areaTreeRoot != "" {
+			root := master.FindNode(nodes, areaTreeRoot)
+			if root == nil {
+				return fmt.Errorf("area %q not found", areaTreeRoot)
+			}
+			nodes = []*master.Node{root}
+		}
+		for _, n := range nodes {
+			n.Truncate(areaTreeDepth)
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, nodes)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"AREA", "CODE", "CONTAINS"})
+		var walk func(n *master.Node, prefix, branch string)
+		walk = func(n *master.Node, prefix, branch string) {
+			tw.Row(prefix+branch+n.Name, n.Code, formatCounts(n.Counts))
+			if branch == "├─ " {
+				prefix += "│  "
+			} else if branch == "└─ " {
+				prefix += "   "
+			}
+			for i, c := range n.Children {
+				next := "├─ "
+				if i == len(n.Children)-1 {
+					next = "└─ "
+				}
+				walk(c, prefix, next)
+			}
+		}
+		for _, n := range nodes {
+			walk(n, "", "")
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+// formatCounts summarizes the areas below a node, e.g. "2 middle, 14 small".
+func formatCounts(c master.Counts) string {
+	var parts []string
+	for _, level := range master.AreaLevels {
+		if n := c[level]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, strings.TrimSuffix(level, "_area")))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 func init() {
 	rootCmd.AddCommand(areaCmd)
 	areaCmd.AddCommand(areaLargeCmd)
 	areaCmd.AddCommand(areaMiddleCmd)
 	areaCmd.AddCommand(areaSmallCmd)
+	areaCmd.AddCommand(areaTreeCmd)
 
 	// large area flags
 	areaLargeCmd.Flags().StringSliceVar(&largeAreaParams.LargeArea, "code", nil, "large area codes")
@@ -164,4 +245,8 @@ func init() {
 	areaSmallCmd.Flags().StringVar(&smallAreaKeyword, "keyword", "", "area name search")
 	areaSmallCmd.Flags().IntVar(&smallAreaStart, "start", 0, "result start position")
 	areaSmallCmd.Flags().IntVar(&smallAreaCount, "count", 0, "results per page")
+
+	// tree flags
+	areaTreeCmd.Flags().StringVar(&areaTreeRoot, "root", "", "show only the subtree of this area code or name (any level)")
+	areaTreeCmd.Flags().IntVar(&areaTreeDepth, "depth", 0, "levels to show, counting the root as 1 (0 for all)")
 }
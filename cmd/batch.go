@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/batch"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	batchConcurrency int
+	batchRate        float64
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch <file|->",
+	Short: "Run many searches from a JSONL file",
+	Long: `Run one gourmet search per line of a JSONL file. Each line is an object
+with the API parameter names (as in GourmetSearchParams) and an optional
+"label". Searches share one client and rate limiter and run concurrently.
+
+JSON output is NDJSON, one {"line", "label", "results" | "error"} object per
+query, in input order. A failed query is reported on its line without
+stopping the run; the command exits non-zero if any failed.`,
+	Example: `  hpp batch queries.jsonl > results.ndjson
+  hpp batch - --concurrency 8 < queries.jsonl
+
+  # queries.jsonl
+  {"label": "shimbashi izakaya", "middle_area": ["Y005"], "genre": ["G001"], "count": 20}
+  {"label": "tamachi ramen", "keyword": "ramen", "small_area": ["X086"]}`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		queries, err := batch.Read(in)
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if dryRun || printCurl {
+			// Print each query's request in input order; nothing is sent,
+			// so there are no results to report.
+			for _, q := range queries {
+				if q.Err != nil {
+					fmt.Fprintln(os.Stderr, q.Err)
+					continue
+				}
+				if err := client.Get("/gourmet/v1/", q.Params, nil); !errors.Is(err, api.ErrDryRun) {
+					return err
+				}
+			}
+			return nil
+		}
+		if cmd.Flags().Changed("rate") {
+			if err := setRate(client, batchRate); err != nil {
+				return err
+			}
+		}
+
+		failed := 0
+		var emit func(batch.Result) error
+		var tw *output.TableWriter
+		if outputFormat == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			emit = func(r batch.Result) error { return enc.Encode(r) }
+		} else {
+			tw = output.NewTableWriter(os.Stdout, []string{"LINE", "LABEL", "FOUND", "RETURNED", "ERROR"})
+			emit = func(r batch.Result) error {
+				if r.Results == nil {
+					tw.Row(strconv.Itoa(r.Line), r.Label, "", "", r.Error)
+				} else {
+					tw.Row(strconv.Itoa(r.Line), r.Label, strconv.Itoa(r.Results.ResultsAvailable), r.Results.ResultsReturned, "")
+				}
+				return nil
+			}
+		}
+		err = batch.Run(client, queries, batchConcurrency, func(r batch.Result) error {
+			if r.Error != "" {
+				failed++
+			}
+			return emit(r)
+		})
+		if tw != nil {
+			tw.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d queries failed", failed, len(queries))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().IntVar(&batchConcurrency, "concurrency", 4, "max searches in flight")
+	batchCmd.Flags().Float64Var(&batchRate, "rate", 0, "max API requests per second, 0 for unlimited (default: the hpp quota set rate)")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/output"
+)
+
+// writeCard prints a shop's details as "Label: value" lines, followed by
+// extra lines given as label, value pairs.
+func writeCard(w io.Writer, s *api.Shop, extra ...[2]string) {
+	genre := s.Genre.Name
+	if s.SubGenre.Name != "" {
+		genre += " / " + s.SubGenre.Name
+	}
+	budget := s.Budget.Average
+	if budget == "" {
+		budget = s.Budget.Name
+	}
+	var amenities []string
+	for _, a := range api.Amenities {
+		if api.HasAmenity(a.Shop(s)) {
+			amenities = append(amenities, a.Name)
+		}
+	}
+
+	tw := output.NewTableWriter(w, nil)
+	for _, kv := range [][2]string{
+		{"Name", s.Name},
+		{"ID", s.ID},
+		{"Genre", genre},
+		{"Catch", s.Catch},
+		{"Budget", budget},
+		{"Area", s.MiddleArea.Name},
+		{"Access", s.Access},
+		{"Address", s.Address},
+		{"Open", s.Open},
+		{"Closed", s.Close},
+		{"Capacity", capacity(s.Capacity)},
+		{"Party", capacity(s.PartyCapacity)},
+		{"Amenities", strings.Join(amenities, ", ")},
+		{"URL", s.URLs.PC},
+	} {
+		if kv[1] != "" {
+			tw.Row(kv[0]+":", kv[1])
+		}
+	}
+	for _, kv := range extra {
+		tw.Row(kv[0]+":", kv[1])
+	}
+	tw.Flush()
+}
+
+// capacity renders a seat count, or "" when unknown.
+func capacity(n api.FlexInt) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d seats", n)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/compare"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var compareDiffOnly bool
+
+// compareResponse is the JSON output of hpp compare. Each row has one
+// value per shop, in the order of Shops.
+type compareResponse struct {
+	Shops []compareShop `json:"shops"`
+	Rows  []compare.Row `json:"rows"`
+}
+
+type compareShop struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <shop-id> <shop-id> [<shop-id>...]",
+	Short: "Compare shops side by side",
+	Long: `Compare shops field by field: budget, capacity, party capacity, hours,
+station, access, area and every amenity. Fields where the shops differ are
+marked with *; amenities differ when one shop has it and another does not.`,
+	Example: `  hpp compare J001234567 J001234568 --format table
+  hpp compare J001234567 J001234568 J001234569 --diff-only --format table`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		shops, missing, err := client.ShopsByID(args)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("shops not found: %s", strings.Join(missing, ", "))
+		}
+		if len(shops) < 2 {
+			return fmt.Errorf("give at least two different shops")
+		}
+
+		resp := compareResponse{Rows: []compare.Row{}}
+		for _, s := range shops {
+			resp.Shops = append(resp.Shops, compareShop{ID: s.ID, Name: s.Name, URL: s.URLs.PC})
+		}
+		for _, r := range compare.Shops(shops) {
+			if r.Differ || !compareDiffOnly {
+				resp.Rows = append(resp.Rows, r)
+			}
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, resp)
+		}
+		headers := []string{"", "FIELD"}
+		for _, s := range shops {
+			headers = append(headers, s.Name)
+		}
+		tw := output.NewTableWriter(os.Stdout, headers)
+		for _, r := range resp.Rows {
+			mark := ""
+			if r.Differ {
+				mark = "*"
+			}
+			tw.Row(append([]string{mark, r.Field}, r.Values...)...)
+		}
+		tw.Row(append([]string{"", "url"}, shopURLs(shops)...)...)
+		tw.Flush()
+		return nil
+	},
+}
+
+func shopURLs(shops []api.Shop) []string {
+	urls := make([]string, len(shops))
+	for i, s := range shops {
+		urls[i] = s.URLs.PC
+	}
+	return urls
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().BoolVar(&compareDiffOnly, "diff-only", false, "only show fields where the shops differ")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/jackchuka/hpp/internal/api"
@@ -13,13 +12,12 @@ var creditcardCmd = &cobra.Command{
 	Use:   "creditcard",
 	Short: "List accepted credit card types",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.CreditCardResponse
-		if err := client.Get("/credit_card/v1/", nil, &resp); err != nil {
+		if err := src.Get("/credit_card/v1/", nil, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
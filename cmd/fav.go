@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/config"
+	"github.com/jackchuka/hpp/internal/fav"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	favNote   string
+	favTags   []string
+	favRating int
+	favLsTags []string
+)
+
+var favCmd = &cobra.Command{
+	Use:   "fav",
+	Short: "Keep favorite shops with notes, tags and ratings",
+	Long: `Keep a local list of favorite shops with a free-text note, tags and a
+personal 1-5 rating. Favorites are stored in favorites.json in the config
+directory (e.g. ~/.config/hpp/favorites.json).
+
+Search with --favorites-only or --tag to only list favorites; table output
+marks favorites with ★.`,
+}
+
+var favAddCmd = &cobra.Command{
+	Use:   "add <shop-id>...",
+	Short: "Add shops to the favorites, or update them",
+	Long: `Add shops to the favorites. Shops already in the favorites are updated:
+--note and --rating replace the saved values, and --tag adds tags.`,
+	Example: `  hpp fav add J001234567 --rating 5 --tag lunch --note "ask for the counter"
+  hpp fav add J001234567 J001234568 --tag team-dinner`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("rating") {
+			if err := fav.ValidRating(favRating); err != nil {
+				return fmt.Errorf("--rating: %w", err)
+			}
+		}
+		store, err := favStore()
+		if err != nil {
+			return err
+		}
+		idx, err := store.Index()
+		if err != nil {
+			return err
+		}
+
+		// Look up the names of new favorites, which also checks the IDs.
+		var lookup []string
+		for _, id := range args {
+			if idx[id] == nil {
+				lookup = append(lookup, id)
+			}
+		}
+		names := map[string]string{}
+		if len(lookup) > 0 {
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			shops, missing, err := client.ShopsByID(lookup)
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("shops not found: %s", strings.Join(missing, ", "))
+			}
+			for _, s := range shops {
+				names[s.ID] = s.Name
+			}
+		}
+
+		for _, id := range args {
+			f, verb := idx[id], "Updated"
+			if f == nil {
+				f, verb = &fav.Favorite{ID: id, Name: names[id], AddedAt: time.Now().UTC()}, "Added"
+				idx[id] = f
+			}
+			if cmd.Flags().Changed("note") {
+				f.Note = favNote
+			}
+			if cmd.Flags().Changed("rating") {
+				f.Rating = favRating
+			}
+			f.AddTags(favTags...)
+			if err := store.Put(*f); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s %s (%s)\n", verb, id, f.Name)
+		}
+		return nil
+	},
+}
+
+var favRemoveCmd = &cobra.Command{
+	Use:   "rm <shop-id>...",
+	Short: "Remove shops from the favorites",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := favStore()
+		if err != nil {
+			return err
+		}
+		if err := store.Remove(args...); err != nil {
+			return err
+		}
+		for _, id := range args {
+			fmt.Fprintf(os.Stderr, "Removed %s\n", id)
+		}
+		return nil
+	},
+}
+
+var favListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List favorites",
+	Example: `  hpp fav ls --format table
+  hpp fav ls --tag lunch`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := favStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		shown := []fav.Favorite{}
+		for _, f := range list {
+			if len(favLsTags) == 0 || hasAnyTag(&f, favLsTags) {
+				shown = append(shown, f)
+			}
+		}
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, shown)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"ID", "NAME", "RATING", "TAGS", "NOTE", "ADDED"})
+		for _, f := range shown {
+			tw.Row(f.ID, f.Name, fav.Stars(f.Rating), strings.Join(f.Tags, ","), f.Note, f.AddedAt.Local().Format(time.DateOnly))
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+func favStore() (*fav.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &fav.Store{Path: filepath.Join(dir, "favorites.json")}, nil
+}
+
+func hasAnyTag(f *fav.Favorite, tags []string) bool {
+	for _, t := range tags {
+		if f.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// favoriteIDs returns the IDs of the favorites with one of the tags, or of
+// all favorites when no tags are given.
+func favoriteIDs(tags []string) ([]string, error) {
+	store, err := favStore()
+	if err != nil {
+		return nil, err
+	}
+	list, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, f := range list {
+		if len(tags) == 0 || hasAnyTag(&f, tags) {
+			ids = append(ids, f.ID)
+		}
+	}
+	if len(ids) == 0 {
+		if len(tags) > 0 {
+			return nil, fmt.Errorf("no favorites tagged %s (see hpp fav ls)", strings.Join(tags, " or "))
+		}
+		return nil, errors.New("no favorites yet; add some with hpp fav add")
+	}
+	return ids, nil
+}
+
+// favMarker returns a function giving "★" for shops in the favorites and ""
+// for others, for the first column of table output.
+func favMarker() (func(id string) string, error) {
+	store, err := favStore()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := store.Index()
+	if err != nil {
+		return nil, err
+	}
+	return func(id string) string {
+		if idx[id] != nil {
+			return "★"
+		}
+		return ""
+	}, nil
+}
+
+func init() {
+	rootCmd.AddCommand(favCmd)
+	favCmd.AddCommand(favAddCmd, favRemoveCmd, favListCmd)
+	favAddCmd.Flags().StringVar(&favNote, "note", "", "free-text note")
+	favAddCmd.Flags().StringSliceVar(&favTags, "tag", nil, "tags to add")
+	favAddCmd.Flags().IntVar(&favRating, "rating", 0, "personal rating from 1 to 5")
+	favListCmd.Flags().StringSliceVar(&favLsTags, "tag", nil, "only favorites with one of these tags")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/jackchuka/hpp/internal/api"
@@ -25,13 +24,12 @@ var genreCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.GenreResponse
-		if err := client.Get("/genre/v1/", genreParams, &resp); err != nil {
+		if err := src.Get("/genre/v1/", genreParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// getResponse is the JSON output of get: found shops in input order and
+// the IDs that were not found.
+type getResponse struct {
+	Shops   []api.Shop `json:"shops"`
+	Missing []string   `json:"missing"`
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get <id>... | -",
+	Short: "Look up shops by ID",
+	Long: `Look up shops by HotPepper shop ID. With "-", IDs are read from stdin, one
+per line (blank lines and lines starting with # are skipped).
+
+IDs are sent 20 per request. Shops are printed in input order; IDs that no
+longer exist are reported separately as "missing".`,
+	Example: `  hpp get J001234567 J001234568
+  hpp search --keyword ramen | jq -r '.results.shop[].id' | hpp get -
+  hpp get - --format table < ids.txt`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := readIDs(args)
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		shops, missing, err := client.ShopsByID(ids)
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			if shops == nil {
+				shops = []api.Shop{}
+			}
+			if missing == nil {
+				missing = []string{}
+			}
+			return output.WriteJSON(os.Stdout, getResponse{Shops: shops, Missing: missing})
+		}
+
+		mark, err := favMarker()
+		if err != nil {
+			return err
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"★", "ID", "NAME", "GENRE", "AREA", "ACCESS", "BUDGET", "URL"})
+		for _, s := range shops {
+			tw.Row(mark(s.ID), s.ID, s.Name, s.Genre.Name, s.MiddleArea.Name, s.Access, s.Budget.Average, s.URLs.PC)
+		}
+		tw.Flush()
+		if len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "\nNot found (%d): %s\n", len(missing), strings.Join(missing, ", "))
+		}
+		return nil
+	},
+}
+
+// readIDs returns the IDs given as arguments, reading stdin for "-".
+func readIDs(args []string) ([]string, error) {
+	var ids []string
+	for _, a := range args {
+		if a != "-" {
+			ids = append(ids, a)
+			continue
+		}
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			ids = append(ids, line)
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("reading IDs: %w", err)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no shop IDs given")
+	}
+	return ids, nil
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+}
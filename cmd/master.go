@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/config"
+	"github.com/jackchuka/hpp/internal/master"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var masterDiffCheck []string
+
+var masterCmd = &cobra.Command{
+	Use:   "master",
+	Short: "Inspect master data snapshots",
+	Long:  "Inspect and compare the master data snapshots saved by hpp sync.",
+}
+
+var masterListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List saved snapshots",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := master.DefaultStore()
+		if err != nil {
+			return err
+		}
+		paths, err := store.List()
+		if err != nil {
+			return err
+		}
+		type entry struct {
+			Path       string         `json:"path"`
+			FetchedAt  string         `json:"fetched_at"`
+			APIVersion string         `json:"api_version"`
+			Tables     []master.Table `json:"tables"`
+		}
+		entries := []entry{}
+		for _, p := range paths {
+			s, err := master.Load(p)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{p, s.FetchedAt.Format(time.RFC3339), s.APIVersion, s.Tables()})
+		}
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, entries)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"FILE", "FETCHED", "API VERSION"})
+		for _, e := range entries {
+			tw.Row(filepath.Base(e.Path), e.FetchedAt, e.APIVersion)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+// brokenReference is a code used in a checked file that no longer exists.
+type brokenReference struct {
+	File  string `json:"file"`
+	Table string `json:"table"`
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+}
+
+var masterDiffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Compare master data between snapshots",
+	Long: `Report codes added, removed and renamed in each master table between two
+snapshots. Each side is a snapshot file, "latest", "previous" (the snapshot
+before latest) or "live" (fetched from the API now). With no arguments,
+latest is compared against live; with one, it is compared against live.
+
+The saved searches and config (saved.json and config.json in the config
+directory) and files given with --check (batch query files, ...) are
+scanned for master codes; the command fails if any of them was removed.`,
+	Example: `  hpp master diff
+  hpp master diff previous latest --format table
+  hpp master diff ~/.cache/hpp/master/master-20260401T090000Z.json live
+  hpp master diff --check queries.jsonl`,
+	Args:         cobra.MaximumNArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		refs := []string{"latest", "live"}
+		copy(refs, args)
+		old, err := loadSnapshotRef(refs[0])
+		if err != nil {
+			return err
+		}
+		new, err := loadSnapshotRef(refs[1])
+		if err != nil {
+			return err
+		}
+		diffs := master.Diff(old, new)
+
+		removed := map[string]brokenReference{}
+		for _, d := range diffs {
+			for _, e := range d.Removed {
+				removed[e.Code] = brokenReference{Table: d.Table, Code: e.Code, Name: e.Name}
+			}
+		}
+		files, err := configFiles()
+		if err != nil {
+			return err
+		}
+		broken := []brokenReference{}
+		for _, file := range append(files, masterDiffCheck...) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			for _, code := range master.ReferencedCodes(data) {
+				if r, ok := removed[code]; ok {
+					r.File = file
+					broken = append(broken, r)
+				}
+			}
+		}
+
+		if outputFormat == "json" {
+			if err := output.WriteJSON(os.Stdout, map[string]any{
+				"old":    snapshotInfo(refs[0], old),
+				"new":    snapshotInfo(refs[1], new),
+				"tables": diffs,
+				"broken": broken,
+			}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Comparing %s (%s) with %s (%s)\n\n",
+				refs[0], old.FetchedAt.Format("2006-01-02 15:04"), refs[1], new.FetchedAt.Format("2006-01-02 15:04"))
+			tw := output.NewTableWriter(os.Stdout, []string{"TABLE", "CHANGE", "CODE", "NAME"})
+			for _, d := range diffs {
+				for _, e := range d.Added {
+					tw.Row(d.Table, "added", e.Code, e.Name)
+				}
+				for _, e := range d.Removed {
+					tw.Row(d.Table, "removed", e.Code, e.Name)
+				}
+				for _, r := range d.Renamed {
+					tw.Row(d.Table, "renamed", r.Code, r.OldName+" → "+r.NewName)
+				}
+			}
+			tw.Flush()
+			if len(diffs) == 0 {
+				fmt.Fprintln(os.Stderr, "No changes")
+			}
+			for _, b := range broken {
+				fmt.Fprintf(os.Stderr, "%s: %s %s (%s) was removed\n", b.File, b.Table, b.Code, b.Name)
+			}
+		}
+		if len(broken) > 0 {
+			return fmt.Errorf("%d referenced code(s) no longer exist", len(broken))
+		}
+		return nil
+	},
+}
+
+// configFiles returns the files in the config directory that can reference
+// master codes: saved searches and the config, when they exist.
+func configFiles() ([]string, error) {
+	store, err := savedStore()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Path()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, path := range []string{store.Path, cfg} {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// loadSnapshotRef resolves a diff argument to a snapshot.
+func loadSnapshotRef(ref string) (*master.Snapshot, error) {
+	switch ref {
+	case "live":
+		client, err := newClient()
+		if err != nil {
+			return nil, err
+		}
+		return master.Fetch(client)
+	case "latest", "previous":
+		store, err := master.DefaultStore()
+		if err != nil {
+			return nil, err
+		}
+		paths, err := store.List()
+		if err != nil {
+			return nil, err
+		}
+		back := 1
+		if ref == "previous" {
+			back = 2
+		}
+		if len(paths) < back {
+			return nil, fmt.Errorf("no %s snapshot: %d saved; run hpp sync", ref, len(paths))
+		}
+		return master.Load(paths[len(paths)-back])
+	}
+	return master.Load(ref)
+}
+
+func snapshotInfo(ref string, s *master.Snapshot) map[string]any {
+	return map[string]any{"source": ref, "fetched_at": s.FetchedAt, "api_version": s.APIVersion}
+}
+
+func init() {
+	rootCmd.AddCommand(masterCmd)
+	masterCmd.AddCommand(masterListCmd)
+	masterCmd.AddCommand(masterDiffCmd)
+	masterDiffCmd.Flags().StringArrayVar(&masterDiffCheck, "check", nil, "file referencing master codes; fail if any was removed (repeatable)")
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/mcp"
+	"github.com/jackchuka/hpp/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run an MCP server over stdio",
+	Long: `Run a Model Context Protocol server on stdin/stdout, exposing gourmet
+search, shop search, shop lookup by ID and the master lists as tools.`,
+	Example: `  hpp mcp
+
+  # Claude Desktop / other MCP clients
+  {"mcpServers": {"hpp": {"command": "hpp", "args": ["mcp"],
+    "env": {"HOTPEPPER_API_KEY": "..."}}}}`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun || printCurl {
+			// Requests would be printed into the protocol stream on stdout.
+			return errors.New("--dry-run and --print-curl cannot be used with mcp")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		return newMCPServer(client).Serve(os.Stdin, os.Stdout)
+	},
+}
+
+// getShopArgs are the arguments of the get_shop tool.
+type getShopArgs struct {
+	ID []string `json:"id"`
+}
+
+// newMCPServer registers one tool per API endpoint. Input schemas are
+// derived from the params structs, described with the matching CLI flags.
+func newMCPServer(client *api.Client) *mcp.Server {
+	s := mcp.NewServer("hpp", version.Version)
+
+	s.AddTool(mcp.Tool{
+		Name:        "search_gourmet",
+		Description: "Search restaurants with the HotPepper Gourmet API (/gourmet/v1/). Codes for area, genre, budget, credit_card and special come from the list_* tools. Shops include access_routes parsed from the access text.",
+		InputSchema: mcp.SchemaFor(api.GourmetSearchParams{}, flagDescriber(searchCmd.Flags(), map[string]string{"large_area": "area"})),
+		Handler: func(args json.RawMessage) (any, error) {
+			var p api.GourmetSearchParams
+			if err := mcp.DecodeArgs(args, &p); err != nil {
+				return nil, err
+			}
+			var resp api.GourmetResponse
+			if err := client.Get("/gourmet/v1/", p, &resp); err != nil {
+				return nil, err
+			}
+			for i := range resp.Results.Shops {
+				resp.Results.Shops[i].ParseAccess()
+			}
+			return resp, nil
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "search_shops",
+		Description: "Search shops by name, kana, address or phone number (/shop/v1/).",
+		InputSchema: mcp.SchemaFor(api.ShopSearchParams{}, flagDescriber(shopCmd.Flags(), nil)),
+		Handler: func(args json.RawMessage) (any, error) {
+			var p api.ShopSearchParams
+			if err := mcp.DecodeArgs(args, &p); err != nil {
+				return nil, err
+			}
+			var resp api.ShopSearchResponse
+			if err := client.Get("/shop/v1/", p, &resp); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "get_shop",
+		Description: "Get full details of restaurants by HotPepper shop ID (e.g. J001234567), up to 20 at once. IDs that no longer exist are listed as missing.",
+		InputSchema: mcp.SchemaFor(getShopArgs{}, func(string) string { return "shop IDs" }),
+		Handler: func(args json.RawMessage) (any, error) {
+			var a getShopArgs
+			if err := mcp.DecodeArgs(args, &a); err != nil {
+				return nil, err
+			}
+			if len(a.ID) == 0 {
+				return nil, errors.New("id is required")
+			}
+			if len(a.ID) > api.MaxIDsPerRequest {
+				return nil, fmt.Errorf("at most %d ids at once, got %d", api.MaxIDsPerRequest, len(a.ID))
+			}
+			shops, missing, err := client.ShopsByID(a.ID)
+			if err != nil {
+				return nil, err
+			}
+			if shops == nil {
+				shops = []api.Shop{}
+			}
+			if missing == nil {
+				missing = []string{}
+			}
+			return getResponse{Shops: shops, Missing: missing}, nil
+		},
+	})
+
+	addMasterTool[api.GenreParams, api.GenreResponse](s, client, "list_genres", "List cuisine genre codes.", "/genre/v1/", genreCmd.Flags(), nil)
+	addMasterTool[struct{}, api.BudgetResponse](s, client, "list_budgets", "List budget range codes.", "/budget/v1/", nil, nil)
+	addMasterTool[struct{}, api.LargeServiceAreaResponse](s, client, "list_large_service_areas", "List large service areas (regions).", "/large_service_area/v1/", nil, nil)
+	addMasterTool[struct{}, api.ServiceAreaResponse](s, client, "list_service_areas", "List service areas (prefectures).", "/service_area/v1/", nil, nil)
+	addMasterTool[api.LargeAreaParams, api.LargeAreaResponse](s, client, "list_large_areas", "List large area codes.", "/large_area/v1/", areaLargeCmd.Flags(), map[string]string{"large_area": "code"})
+	addMasterTool[api.MiddleAreaParams, api.MiddleAreaResponse](s, client, "list_middle_areas", "List middle area codes, optionally within large areas.", "/middle_area/v1/", areaMiddleCmd.Flags(), map[string]string{"middle_area": "code"})
+	addMasterTool[api.SmallAreaParams, api.SmallAreaResponse](s, client, "list_small_areas", "List small area codes, optionally within middle areas.", "/small_area/v1/", areaSmallCmd.Flags(), map[string]string{"small_area": "code"})
+	addMasterTool[struct{}, api.CreditCardResponse](s, client, "list_credit_cards", "List credit card codes.", "/credit_card/v1/", nil, nil)
+	addMasterTool[api.SpecialParams, api.SpecialResponse](s, client, "list_specials", "List special feature codes.", "/special/v1/", specialListCmd.Flags(), map[string]string{"special": "code", "special_category": "category"})
+	addMasterTool[api.SpecialCategoryParams, api.SpecialCategoryResponse](s, client, "list_special_categories", "List special feature category codes.", "/special_category/v1/", specialCategoryCmd.Flags(), map[string]string{"special_category": "code"})
+	return s
+}
+
+// addMasterTool registers a tool returning the results of a master list
+// endpoint called with params of type P.
+func addMasterTool[P, R any](s *mcp.Server, client *api.Client, name, description, path string, flags *pflag.FlagSet, aliases map[string]string) {
+	var describe func(string) string
+	if flags != nil {
+		describe = flagDescriber(flags, aliases)
+	}
+	s.AddTool(mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: mcp.SchemaFor(*new(P), describe),
+		Handler: func(args json.RawMessage) (any, error) {
+			var p P
+			if err := mcp.DecodeArgs(args, &p); err != nil {
+				return nil, err
+			}
+			var resp R
+			if err := client.Get(path, p, &resp); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		},
+	})
+}
+
+// flagDescriber describes a parameter with the usage of the CLI flag of the
+// same name (with "_" as "-"), or of the flag named in aliases.
+func flagDescriber(flags *pflag.FlagSet, aliases map[string]string) func(string) string {
+	return func(name string) string {
+		flag := strings.ReplaceAll(name, "_", "-")
+		if alias, ok := aliases[name]; ok {
+			flag = alias
+		}
+		if f := flags.Lookup(flag); f != nil {
+			return f.Usage
+		}
+		return ""
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
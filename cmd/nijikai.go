@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Defaults for hpp nijikai: a first party is assumed to end when the
+// first venue closes, but no later than nijikaiLatestEnd, and the second
+// venue must stay open nijikaiMinStay after that.
+const (
+	nijikaiLatestEnd  = 22 * 60
+	nijikaiUnknownEnd = 21 * 60
+	nijikaiMinStay    = 60
+	nijikaiRange      = 3 // 1km
+	nijikaiCount      = 20
+)
+
+var (
+	nijikaiOpts  searchFlags
+	nijikaiAfter string
+	nijikaiDate  string
+)
+
+// nijikaiResponse is the JSON output of hpp nijikai. After is when the
+// first party ends, e.g. "22:00"; venues are nearest first.
+type nijikaiResponse struct {
+	From   api.Shop      `json:"from"`
+	Date   string        `json:"date"`
+	After  string        `json:"after"`
+	Venues []nijikaiShop `json:"venues"`
+}
+
+type nijikaiShop struct {
+	Shop      api.Shop `json:"shop"`
+	Distance  int      `json:"distance"` // meters from the first venue
+	OpenUntil string   `json:"open_until"`
+}
+
+var nijikaiCmd = &cobra.Command{
+	Use:   "nijikai <shop-id>",
+	Short: "Find a second venue near the first one, open late",
+	Long: `Find second-party (nijikai) venues within walking distance of a first
+venue, open after the first party ends, nearest first.
+
+The first party is taken to end when the first venue closes that day, or
+at 22:00 if it is open later (21:00 if its hours are unknown); set it with
+--after. Venues must be open at that time, by their parsed opening hours,
+and stay open at least an hour longer. Venues whose hours cannot be parsed
+are left out.
+
+Takes the hpp search flags for the second venue, e.g. --karaoke,
+--free-drink, --midnight, --party-capacity or --genre. The search is
+centered on the first venue, within --range 3 (1km) unless given.`,
+	Example: `  hpp nijikai J001234567 --format table
+  hpp nijikai J001234567 --karaoke --party-capacity 15
+  hpp nijikai J001234567 --genre バー --free-drink --after 21:30 --date 2026-12-18`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range []string{"lat", "lng", "near", "from"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s cannot be used: nijikai searches around the first venue", name)
+			}
+		}
+		return nijikaiOpts.parse(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day := time.Now()
+		if cmd.Flags().Changed("date") {
+			var err error
+			if day, err = time.ParseInLocation(time.DateOnly, nijikaiDate, time.Local); err != nil {
+				return fmt.Errorf("--date: want YYYY-MM-DD, got %q", nijikaiDate)
+			}
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		shops, missing, err := client.ShopsByID(args)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("shop not found: %s", args[0])
+		}
+		first := shops[0]
+
+		after := nijikaiUnknownEnd
+		if cmd.Flags().Changed("after") {
+			if after, err = api.ParseClock(nijikaiAfter); err != nil {
+				return fmt.Errorf("--after: %w", err)
+			}
+		} else if closing, ok := first.Hours().Closing(day.Weekday()); ok {
+			after = min(closing, nijikaiLatestEnd)
+		}
+		fmt.Fprintf(os.Stderr, "Looking for venues near %s open after %s on %s (set with --after)\n",
+			first.Name, api.FormatClock(after), day.Format("2006-01-02 Mon"))
+
+		p := &nijikaiOpts.params
+		p.Lat, p.Lng = &first.Lat, &first.Lng
+		if p.Range == nil {
+			r := nijikaiRange
+			p.Range = &r
+		}
+		if p.Count == nil {
+			n := nijikaiCount
+			p.Count = &n
+		}
+		unknown := 0
+		nijikaiOpts.filters = append(nijikaiOpts.filters, func(s *api.Shop) bool {
+			if s.ID == first.ID {
+				return false
+			}
+			h := s.Hours()
+			if h == nil {
+				unknown++
+				return false
+			}
+			until, open := h.OpenUntil(day.Weekday(), after)
+			return open && until >= after+nijikaiMinStay
+		})
+		results, err := nijikaiOpts.search(client)
+		if err != nil {
+			return err
+		}
+		if unknown > 0 {
+			fmt.Fprintf(os.Stderr, "Left out %d shops with opening hours that could not be read\n", unknown)
+		}
+
+		origin := geo.Point{Lat: first.Lat, Lng: first.Lng}
+		resp := nijikaiResponse{From: first, Date: day.Format(time.DateOnly), After: api.FormatClock(after), Venues: []nijikaiShop{}}
+		for _, s := range results.Shops {
+			until, _ := s.Hours().OpenUntil(day.Weekday(), after)
+			resp.Venues = append(resp.Venues, nijikaiShop{
+				Shop:      s,
+				Distance:  int(geo.Distance(origin, geo.Point{Lat: s.Lat, Lng: s.Lng})),
+				OpenUntil: api.FormatClock(until),
+			})
+		}
+		slices.SortStableFunc(resp.Venues, func(a, b nijikaiShop) int { return cmp.Compare(a.Distance, b.Distance) })
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, resp)
+		}
+		mark, err := favMarker()
+		if err != nil {
+			return err
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"★", "DISTANCE", "NAME", "GENRE", "OPEN UNTIL", "BUDGET", "PARTY", "URL"})
+		for _, v := range resp.Venues {
+			tw.Row(mark(v.Shop.ID), geo.FormatDistance(float64(v.Distance)), v.Shop.Name, v.Shop.Genre.Name,
+				v.OpenUntil, v.Shop.Budget.Average, capacity(v.Shop.PartyCapacity), v.Shop.URLs.PC)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nijikaiCmd)
+	nijikaiOpts.register(nijikaiCmd.Flags())
+	nijikaiCmd.Flags().StringVar(&nijikaiAfter, "after", "", "when the first party ends, e.g. 21:30 or 翌0:30 (default: when the first venue closes, at most 22:00)")
+	nijikaiCmd.Flags().StringVar(&nijikaiDate, "date", "", "date of the party as YYYY-MM-DD (default today)")
+}
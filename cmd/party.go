@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/filter"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/party"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Defaults for hpp party: the search radius around --near, --lat/--lng or
+// --from, and the number of shops listed.
+const (
+	partyRange = 3 // 1km
+	partyCount = 10
+)
+
+var (
+	partyOpts   searchFlags
+	partyPeople int
+	partyBudget int
+)
+
+// partyResponse is the JSON output of hpp party. Criteria are those of
+// the search that found the shops; Relaxed is set when it is not the
+// strictest one.
+type partyResponse struct {
+	People    int               `json:"people"`
+	BudgetYen int               `json:"budget_yen,omitempty"`
+	Criteria  []party.Criterion `json:"criteria"`
+	Relaxed   bool              `json:"relaxed"`
+	Shops     []partyShop       `json:"shops"`
+}
+
+type partyShop struct {
+	Shop      api.Shop      `json:"shop"`
+	Distance  *int          `json:"distance,omitempty"` // meters from --near, --lat/--lng or --from
+	Checks    []party.Check `json:"checks"`
+	Rationale string        `json:"rationale"`
+}
+
+var partyCmd = &cobra.Command{
+	Use:   "party",
+	Short: "Shortlist venues for a large group",
+	Long: `Shortlist banquet venues for a group: seats for --people, private rooms,
+private rental (charter), courses and all-you-can-drink, within --budget
+yen per person.
+
+When no shop meets every criterion, the search is repeated with looser
+criteria, dropping charter, then private rooms, courses, free drink and
+finally the budget; the group size is always kept. Shops are listed with
+the criteria each one meets, those meeting the most first.
+
+Takes the hpp search flags to narrow the search, e.g. --genre, --station
+or --where, except --budget, which is the budget in yen here, and
+--recent-last; --party-capacity is set from --people. Give a place with --near,
+--lat/--lng or --from, searched within --range 3 (1km) unless given, or
+an area such as --middle-area. --count is the number of shops listed
+(default 10).`,
+	Example: `  hpp party --people 30 --budget 5000 --near 新橋 --format table
+  hpp party --people 12 --middle-area Y005 --genre 居酒屋
+  hpp party --people 40 --budget 6000 --lat 35.6662 --lng 139.7580 --range 4`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if partyPeople < 1 {
+			return fmt.Errorf("--people is required")
+		}
+		if partyBudget < 0 {
+			return fmt.Errorf("--budget must not be negative")
+		}
+		if cmd.Flags().Changed("party-capacity") {
+			return fmt.Errorf("--party-capacity cannot be used: party asks for --people seats")
+		}
+		if cmd.Flags().Changed("recent-last") {
+			return fmt.Errorf("--recent-last cannot be used: party lists the shops meeting the most criteria first")
+		}
+		if err := partyOpts.parse(cmd.Flags()); err != nil {
+			return err
+		}
+		p := &partyOpts.params
+		if p.Count != nil && *p.Count < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+		if p.Range != nil && (*p.Range < 1 || *p.Range > 5) {
+			return fmt.Errorf("--range must be from 1 to 5")
+		}
+		if (p.Lat == nil) != (p.Lng == nil) {
+			return fmt.Errorf("--lat and --lng must be given together")
+		}
+		if p.Lat == nil && p.LargeServiceArea == nil && len(p.ServiceArea)+len(p.LargeArea)+len(p.MiddleArea)+len(p.SmallArea) == 0 {
+			return fmt.Errorf("give --near, --lat/--lng, --from or an area such as --middle-area")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base := partyOpts.params
+		count := partyCount
+		if base.Count != nil {
+			count = *base.Count
+		}
+		pageSize := api.MaxGourmetCount
+		base.Count = &pageSize
+		var origin *geo.Point
+		if base.Lat != nil {
+			origin = &geo.Point{Lat: *base.Lat, Lng: *base.Lng}
+			if base.Range == nil {
+				r := partyRange
+				base.Range = &r
+			}
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		req := party.Request{People: partyPeople, BudgetYen: partyBudget}
+		tiers := req.Tiers()
+		var shops []api.Shop
+		var used []party.Criterion
+		for i, tier := range tiers {
+			filters := slices.Clone(partyOpts.filters)
+			if slices.Contains(tier, party.Budget) {
+				filters = append(filters, func(s *api.Shop) bool { return req.Met(s, party.Budget).Met })
+			}
+			p, err := req.Params(base, tier)
+			if err != nil {
+				return err
+			}
+			res, err := filter.Collect(client, p, api.MaxGourmetCount, partyOpts.maxPages, filters...)
+			if err != nil {
+				return err
+			}
+			if len(res.Shops) > 0 || i == len(tiers)-1 {
+				shops, used = res.Shops, tier
+				fmt.Fprintf(os.Stderr, "Found %d shops with %s\n", len(shops), req.Describe(tier))
+				break
+			}
+			fmt.Fprintf(os.Stderr, "No shops with %s; relaxing criteria\n", req.Describe(tier))
+		}
+
+		resp := partyResponse{People: partyPeople, BudgetYen: partyBudget, Criteria: used, Relaxed: len(used) < len(tiers[0]), Shops: []partyShop{}}
+		met := map[string]int{}
+		for _, s := range shops {
+			ps := partyShop{Shop: s, Checks: req.Rationale(&s)}
+			ps.Rationale = party.Explain(ps.Checks)
+			if origin != nil {
+				d := int(geo.Distance(*origin, geo.Point{Lat: s.Lat, Lng: s.Lng}))
+				ps.Distance = &d
+			}
+			for _, c := range ps.Checks {
+				if c.Met {
+					met[s.ID]++
+				}
+			}
+			resp.Shops = append(resp.Shops, ps)
+		}
+		slices.SortStableFunc(resp.Shops, func(a, b partyShop) int {
+			if c := cmp.Compare(met[b.Shop.ID], met[a.Shop.ID]); c != 0 || a.Distance == nil {
+				return c
+			}
+			return cmp.Compare(*a.Distance, *b.Distance)
+		})
+		if len(resp.Shops) > count {
+			resp.Shops = resp.Shops[:count]
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, resp)
+		}
+		mark, err := favMarker()
+		if err != nil {
+			return err
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"★", "NAME", "PARTY", "BUDGET", "DISTANCE", "RATIONALE", "URL"})
+		for _, ps := range resp.Shops {
+			dist := ""
+			if ps.Distance != nil {
+				dist = geo.FormatDistance(float64(*ps.Distance))
+			}
+			tw.Row(mark(ps.Shop.ID), ps.Shop.Name, capacity(ps.Shop.PartyCapacity), ps.Shop.Budget.Average, dist, ps.Rationale, ps.Shop.URLs.PC)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(partyCmd)
+	search := pflag.NewFlagSet("search", pflag.ContinueOnError)
+	partyOpts.register(search)
+	search.VisitAll(func(fl *pflag.Flag) {
+		// --budget is the per-person budget in yen rather than budget codes.
+		if fl.Name != "budget" {
+			partyCmd.Flags().AddFlag(fl)
+		}
+	})
+	partyCmd.Flags().IntVar(&partyPeople, "people", 0, "group size (required)")
+	partyCmd.Flags().IntVar(&partyBudget, "budget", 0, "most to spend per person in yen")
+}
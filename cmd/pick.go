@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/pick"
+	"github.com/jackchuka/hpp/internal/rank"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pickOpts    searchFlags
+	pickNumber  int
+	pickSeed    uint64
+	pickWeights []string
+)
+
+// pickResponse is the JSON output of hpp pick.
+type pickResponse struct {
+	Seed       uint64       `json:"seed"`
+	Candidates int          `json:"candidates"`
+	Rank       string       `json:"rank,omitempty"`
+	Profile    rank.Profile `json:"profile"`
+	Picks      []pickedShop `json:"picks"`
+}
+
+type pickedShop struct {
+	pick.Candidate
+	Reason string `json:"reason"`
+}
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Pick a shop at random from the search results",
+	Long: `Search with the hpp search flags, then pick one shop (or -n shops) at
+random and print its details and why it was chosen.
+
+Each shop's chance is proportional to 1 plus its score from a preference
+profile, as for hpp search --rank: either a profile from the config named
+with --rank, or one given with --weight factor=weight, where distance
+favors shops closer to the search point (--lat/--lng, --near or --from),
+budget favors lower average spend and an amenity name such as
+private_room favors shops that have it. With --weight, the distance and
+budget limits come from the candidates: the farthest one, and midway
+between the cheapest and priciest average budgets. The seed is printed so
+a pick can be repeated with --seed.
+
+Up to 100 candidates are fetched unless --count is given.`,
+	Example: `  hpp pick --near 浜松町 --range 2 --lunch --format table
+  hpp pick --near 新橋 --genre 居酒屋 -n 3 --weight distance=2 --weight budget --weight private_room
+  hpp pick --middle-area Y005 --lunch --avoid-recent 14d --seed 42
+  hpp pick --near 浜松町 --rank lunch -n 2`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if pickNumber < 1 {
+			return fmt.Errorf("-n must be at least 1")
+		}
+		return pickOpts.parse(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, err := pick.ParseWeights(pickWeights)
+		if err != nil {
+			return fmt.Errorf("--weight: %w", err)
+		}
+		var origin *geo.Point
+		if pickOpts.params.Lat != nil && pickOpts.params.Lng != nil {
+			origin = &geo.Point{Lat: *pickOpts.params.Lat, Lng: *pickOpts.params.Lng}
+		}
+		if pickOpts.profile != nil {
+			if len(pickWeights) > 0 {
+				return fmt.Errorf("--weight cannot be combined with --rank")
+			}
+			profile = *pickOpts.profile
+		} else if profile.Distance != 0 && origin == nil {
+			return fmt.Errorf("--weight distance needs --lat/--lng, --near or --from")
+		}
+		if !cmd.Flags().Changed("seed") {
+			pickSeed = uint64(time.Now().UnixNano())
+		}
+		if pickOpts.params.Count == nil {
+			count := api.MaxGourmetCount
+			pickOpts.params.Count = &count
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		results, err := pickOpts.search(client)
+		if err != nil {
+			return err
+		}
+		if len(results.Shops) == 0 {
+			return fmt.Errorf("no shops match the search")
+		}
+
+		if pickOpts.profile == nil {
+			pick.SetLimits(&profile, results.Shops, origin)
+		}
+		cands := pick.Weigh(results.Shops, origin, &profile)
+		picks := pick.Choose(rand.New(rand.NewPCG(pickSeed, pickSeed)), cands, pickNumber)
+		resp := pickResponse{Seed: pickSeed, Candidates: len(cands), Rank: pickOpts.rank, Profile: profile}
+		for _, p := range picks {
+			resp.Picks = append(resp.Picks, pickedShop{Candidate: p, Reason: pick.Reason(p, len(cands))})
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, resp)
+		}
+		fmt.Fprintf(os.Stderr, "Picked %d of %d candidates (seed %d; pass --seed %d to repeat)\n\n",
+			len(picks), len(cands), pickSeed, pickSeed)
+		for i, p := range resp.Picks {
+			if i > 0 {
+				fmt.Println()
+			}
+			extra := [][2]string{{"Why", p.Reason}}
+			if origin != nil {
+				d := geo.Distance(*origin, geo.Point{Lat: p.Shop.Lat, Lng: p.Shop.Lng})
+				extra = append([][2]string{{"Distance", geo.FormatDistance(d)}}, extra...)
+			}
+			writeCard(os.Stdout, &p.Shop, extra...)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+	pickOpts.register(pickCmd.Flags())
+	pickOpts.registerRank(pickCmd.Flags())
+	pickCmd.Flags().IntVarP(&pickNumber, "number", "n", 1, "number of shops to pick")
+	pickCmd.Flags().Uint64Var(&pickSeed, "seed", 0, "random seed, to repeat a pick (default: time-based)")
+	pickCmd.Flags().StringArrayVar(&pickWeights, "weight", nil, "favor shops by factor=weight: distance, budget or an amenity name (repeatable)")
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/config"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quotaDaily   int
+	quotaMonthly int
+	quotaAction  string
+	quotaRate    float64
+	quotaBurst   int
+)
+
+// quotaTracker counts this process's requests; it is flushed on exit.
+var quotaTracker *quota.Tracker
+
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show API usage against the configured caps",
+	Long: `Show how many requests this machine has made with the current API key
+today and this month (days in Japan time), against the soft caps set with
+hpp quota set.
+
+Once a cap is reached, commands print a warning or, with --action refuse,
+stop before sending more requests.`,
+	Example: `  hpp quota --format table
+  hpp quota set --daily 3000 --action refuse
+  hpp quota set --rate 2 --burst 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey := os.Getenv("HOTPEPPER_API_KEY")
+		if apiKey == "" {
+			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		store, err := usageStore()
+		if err != nil {
+			return err
+		}
+		key := quota.KeyID(apiKey)
+		u, err := store.Load(key)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		type period struct {
+			Period    string `json:"period"`
+			Used      int    `json:"used"`
+			Cap       int    `json:"cap"`
+			Remaining *int   `json:"remaining,omitempty"`
+		}
+		newPeriod := func(name string, used, limit int) period {
+			p := period{Period: name, Used: used, Cap: limit}
+			if limit > 0 {
+				r := max(limit-used, 0)
+				p.Remaining = &r
+			}
+			return p
+		}
+		report := struct {
+			Key       string           `json:"key"`
+			Today     period           `json:"today"`
+			Month     period           `json:"month"`
+			Action    string           `json:"action"`
+			RateLimit config.RateLimit `json:"rate_limit"`
+		}{
+			Key:       key,
+			Today:     newPeriod(quota.Day(now), u.Today(now), cfg.Quota.Daily),
+			Month:     newPeriod(quota.Month(now), u.ThisMonth(now), cfg.Quota.Monthly),
+			Action:    cfg.Quota.Action,
+			RateLimit: cfg.RateLimit,
+		}
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, report)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"PERIOD", "USED", "CAP", "REMAINING"})
+		for _, p := range []period{report.Today, report.Month} {
+			limit, remaining := "-", "-"
+			if p.Remaining != nil {
+				limit, remaining = strconv.Itoa(p.Cap), strconv.Itoa(*p.Remaining)
+			}
+			tw.Row(p.Period, strconv.Itoa(p.Used), limit, remaining)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+var quotaSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set request caps and the rate limit",
+	Long: `Save soft caps on daily and monthly requests (0 removes a cap), the action
+taken once one is reached, and the rate limit applied to every command.
+Only the given flags change.`,
+	Example: `  hpp quota set --daily 3000 --monthly 50000
+  hpp quota set --action refuse
+  hpp quota set --rate 0   # no rate limit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		f := cmd.Flags()
+		if f.Changed("daily") {
+			cfg.Quota.Daily = quotaDaily
+		}
+		if f.Changed("monthly") {
+			cfg.Quota.Monthly = quotaMonthly
+		}
+		if f.Changed("action") {
+			cfg.Quota.Action = quotaAction
+		}
+		if f.Changed("rate") {
+			cfg.RateLimit.PerSecond = quotaRate
+		}
+		if f.Changed("burst") {
+			cfg.RateLimit.Burst = quotaBurst
+		}
+		if err := cfg.Quota.Validate(); err != nil {
+			return err
+		}
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saved %s\n", path)
+		return nil
+	},
+}
+
+func loadConfig() (*config.Config, error) {
+	path, err := config.Path()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+func usageStore() (*quota.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &quota.Store{Path: filepath.Join(dir, "usage.json")}, nil
+}
+
+// newQuotaTracker returns the process's tracker for apiKey, creating it on
+// first use.
+func newQuotaTracker(apiKey string, caps quota.Caps) (*quota.Tracker, error) {
+	if quotaTracker != nil {
+		return quotaTracker, nil
+	}
+	store, err := usageStore()
+	if err != nil {
+		return nil, err
+	}
+	t, err := quota.NewTracker(store, apiKey, caps)
+	if err != nil {
+		return nil, err
+	}
+	t.Warn = func(msg string) { fmt.Fprintf(os.Stderr, "warning: %s; see hpp quota\n", msg) }
+	quotaTracker = t
+	return t, nil
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+	quotaCmd.AddCommand(quotaSetCmd)
+	f := quotaSetCmd.Flags()
+	f.IntVar(&quotaDaily, "daily", 0, "max requests per day (0 for no cap)")
+	f.IntVar(&quotaMonthly, "monthly", 0, "max requests per month (0 for no cap)")
+	f.StringVar(&quotaAction, "action", quota.ActionWarn, "when a cap is reached: warn or refuse")
+	f.Float64Var(&quotaRate, "rate", 5, "max requests per second (0 for unlimited)")
+	f.IntVar(&quotaBurst, "burst", 5, "max requests sent at once before the rate applies")
+}
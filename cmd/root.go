@@ -1,29 +1,143 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/quota"
 	"github.com/spf13/cobra"
 )
 
 var (
 	outputFormat string
+	dryRun       bool
+	printCurl    bool
+	verbose      bool
+	logLevel     string
+	logFormat    string
+	retries      int
 )
 
+// logger writes diagnostics to stderr as configured by the logging flags.
+var logger = slog.New(slog.DiscardHandler)
+
 var rootCmd = &cobra.Command{
 	Use:   "hpp",
 	Short: "HotPepper Gourmet API CLI",
 	Long:  "Search Japanese restaurants using the HotPepper Gourmet API.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if dryRun || printCurl {
+			// The dry run ends with api.ErrDryRun, which is not a failure.
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		if retries < 0 {
+			return fmt.Errorf("--retries must not be negative")
+		}
+		l, err := newLogger()
+		if err != nil {
+			return err
+		}
+		logger = l
+		return nil
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if quotaTracker != nil {
+		if ferr := quotaTracker.Flush(); ferr != nil {
+			fmt.Fprintf(os.Stderr, "warning: saving request count: %v\n", ferr)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, api.ErrDryRun) {
+			return
+		}
 		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, quota.ErrCapReached) {
+			fmt.Fprintln(os.Stderr, "Raise the cap with hpp quota set, or see hpp quota.")
+		}
 		os.Exit(1)
 	}
 }
 
+// newLogger builds the stderr logger from --verbose, --log-level and
+// --log-format.
+func newLogger() (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return nil, fmt.Errorf("--log-level: must be debug, info, warn or error")
+	}
+	if verbose {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	switch logFormat {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("--log-format: must be text or json")
+	}
+}
+
+// newClient returns an API client using the key from HOTPEPPER_API_KEY,
+// paced and counted as configured. With --dry-run or --print-curl, the
+// client prints requests instead of sending them, and no key is needed.
+func newClient() (*api.Client, error) {
+	apiKey := os.Getenv("HOTPEPPER_API_KEY")
+	if dryRun || printCurl {
+		c := api.NewClient(apiKey)
+		c.HTTPClient = &http.Client{Transport: &api.DryRunTransport{
+			Out:  os.Stdout,
+			JSON: outputFormat == "json" && !printCurl,
+			Curl: printCurl,
+		}}
+		c.Logger = logger
+		return c, nil
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	tracker, err := newQuotaTracker(apiKey, cfg.Quota)
+	if err != nil {
+		return nil, err
+	}
+	c := api.NewClient(apiKey)
+	c.Logger = logger
+	c.Limiter = api.NewRateLimiter(cfg.RateLimit.PerSecond, cfg.RateLimit.Burst)
+	c.Retries = retries
+	c.Quota = tracker
+	return c, nil
+}
+
+// setRate paces c to perSecond requests per second, keeping the configured
+// burst.
+func setRate(c *api.Client, perSecond float64) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	c.Limiter = api.NewRateLimiter(perSecond, cfg.RateLimit.Burst)
+	return nil
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "json", "output format: table or json")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the API request (key redacted) instead of sending it")
+	rootCmd.PersistentFlags().BoolVar(&printCurl, "print-curl", false, "print the API request as a curl command instead of sending it")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "log every API request (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text or json")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 0, "retry rate-limited (429), failed (5xx) or timed-out requests up to this many times; each retry counts against the quota")
 }
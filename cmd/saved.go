@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/config"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/saved"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	savedRunOpts searchFlags
+	savedForce   bool
+)
+
+var savedCmd = &cobra.Command{
+	Use:   "saved",
+	Short: "Save searches under a name and run them again",
+	Long: `Save gourmet searches under a name and run them again, optionally with
+overrides. Searches are stored in saved.json in the config directory
+(e.g. ~/.config/hpp/saved.json); names given to code flags and --near are
+resolved when saving.`,
+}
+
+var savedAddCmd = &cobra.Command{
+	Use:   "add <name> -- <search flags>",
+	Short: "Save a search",
+	Example: `  hpp saved add hamamatsucho-lunch -- --near 浜松町 --range 2 --lunch --budget B009 --non-smoking
+  hpp saved add team-dinner --force -- --middle-area Y005 --party-capacity 10 --no-karaoke`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 1 {
+			return fmt.Errorf("usage: hpp saved add <name> -- <search flags>")
+		}
+		name := args[0]
+		if err := saved.ValidName(name); err != nil {
+			return err
+		}
+
+		fs := pflag.NewFlagSet("search", pflag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		var sf searchFlags
+		sf.register(fs)
+		if err := fs.Parse(args[1:]); err != nil {
+			return fmt.Errorf("search flags: %w", err)
+		}
+		if fs.NArg() > 0 {
+			return fmt.Errorf("search flags: unexpected argument %q", fs.Arg(0))
+		}
+		if fs.Changed("from") {
+			return fmt.Errorf("--from cannot be saved; save --near or --lat/--lng instead")
+		}
+		if err := sf.parse(fs); err != nil {
+			return err
+		}
+
+		store, err := savedStore()
+		if err != nil {
+			return err
+		}
+		if !savedForce {
+			if _, err := store.Get(name); err == nil {
+				return fmt.Errorf("%s is already saved; use --force to replace it", name)
+			} else if !errors.Is(err, saved.ErrNotFound) {
+				return err
+			}
+		}
+		replaced, err := store.Put(saved.Search{
+			Name:      name,
+			Params:    sf.params,
+			Filters:   sf.clientArgs(fs),
+			CreatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+		if replaced {
+			fmt.Fprintf(os.Stderr, "Replaced %s\n", name)
+		} else {
+			fmt.Fprintf(os.Stderr, "Saved %s\n", name)
+		}
+		return nil
+	},
+}
+
+var savedRunCmd = &cobra.Command{
+	Use:   "run <name> [search flags]",
+	Short: "Run a saved search",
+	Long: `Run a saved search. Search flags given here are merged on top: they replace
+the saved value of the same parameter and add client-side filters;
+--no-<amenity> also turns off a saved --<amenity>.`,
+	Example: `  hpp saved run hamamatsucho-lunch --format table
+  hpp saved run hamamatsucho-lunch --count 30 --wifi`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return savedRunOpts.parse(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedStore()
+		if err != nil {
+			return err
+		}
+		s, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Rebuild the saved client-side filters from their flags.
+		fs := pflag.NewFlagSet("saved", pflag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		var base searchFlags
+		base.register(fs)
+		if err := fs.Parse(s.Filters); err != nil {
+			return fmt.Errorf("saved search %s: %w", s.Name, err)
+		}
+		if err := base.parse(fs); err != nil {
+			return fmt.Errorf("saved search %s: %w", s.Name, err)
+		}
+
+		run := &savedRunOpts
+		// --no-<amenity> turns off the saved filter for it, too.
+		var off []string
+		for _, a := range api.Amenities {
+			if *run.without[amenityFlag(a)] {
+				off = append(off, a.Name)
+			}
+		}
+		if run.params, err = saved.Merge(s.Params, run.params, off...); err != nil {
+			return err
+		}
+		run.filters = append(base.filters, run.filters...)
+		if run.favIDs == nil {
+			run.favIDs = base.favIDs
+		}
+		if run.recent == nil {
+			run.recent = base.recent
+		}
+		if !cmd.Flags().Changed("max-pages") {
+			run.maxPages = base.maxPages
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		results, err := run.search(client)
+		if err != nil {
+			return err
+		}
+		return run.write(results)
+	},
+}
+
+var savedListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		if outputFormat == "json" {
+			if list == nil {
+				list = []saved.Search{}
+			}
+			return output.WriteJSON(os.Stdout, list)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"NAME", "SEARCH", "CREATED"})
+		for _, s := range list {
+			desc, err := describeSaved(&s)
+			if err != nil {
+				return err
+			}
+			tw.Row(s.Name, desc, s.CreatedAt.Local().Format(time.DateTime))
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+var savedShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedStore()
+		if err != nil {
+			return err
+		}
+		s, err := store.Get(args[0])
+		if err != nil {
+			return err
+		}
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, s)
+		}
+		vals, err := query.Values(s.Params)
+		if err != nil {
+			return err
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"PARAM", "VALUE"})
+		for _, k := range slices.Sorted(maps.Keys(vals)) {
+			tw.Row(k, strings.Join(vals[k], ","))
+		}
+		for _, f := range s.Filters {
+			flag, v, _ := strings.Cut(f, "=")
+			tw.Row(flag, v)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+var savedRemoveCmd = &cobra.Command{
+	Use:   "rm <name>...",
+	Short: "Delete saved searches",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := savedStore()
+		if err != nil {
+			return err
+		}
+		for _, name := range args {
+			if err := store.Remove(name); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Removed %s\n", name)
+		}
+		return nil
+	},
+}
+
+func savedStore() (*saved.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &saved.Store{Path: filepath.Join(dir, "saved.json")}, nil
+}
+
+// describeSaved summarizes a saved search on one line, e.g.
+// "lunch=1 middle_area=Y005 --no-karaoke".
+func describeSaved(s *saved.Search) (string, error) {
+	vals, err := query.Values(s.Params)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(vals)) {
+		parts = append(parts, k+"="+strings.Join(vals[k], ","))
+	}
+	parts = append(parts, s.Filters...)
+	return strings.Join(parts, " "), nil
+}
+
+func init() {
+	rootCmd.AddCommand(savedCmd)
+	savedCmd.AddCommand(savedAddCmd, savedRunCmd, savedListCmd, savedShowCmd, savedRemoveCmd)
+	savedAddCmd.Flags().BoolVar(&savedForce, "force", false, "replace a search saved under the same name")
+	savedRunOpts.register(savedRunCmd.Flags())
+	savedRunOpts.registerRank(savedRunCmd.Flags())
+}
@@ -0,0 +1,528 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/filter"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/master"
+	"github.com/jackchuka/hpp/internal/rank"
+	"github.com/spf13/pflag"
+)
+
+// searchFlags holds the gourmet search flags shared by hpp search and the
+// commands built on it. Call register in init and parse in PreRunE; parse
+// fills params, the client-side filters and the --from participants.
+type searchFlags struct {
+	// Values bound to flags (pointer fields need indirection)
+	keyword          string
+	name             string
+	nameKana         string
+	nameAny          string
+	tel              string
+	address          string
+	lat              float64
+	lng              float64
+	rangeCode        int
+	datum            string
+	near             string
+	from             []string
+	meet             string
+	maxWalk          int
+	station          string
+	where            string
+	excludeGenre     []string
+	excludeArea      []string
+	excludeKeyword   []string
+	maxPages         int
+	favoritesOnly    bool
+	tags             []string
+	avoidRecent      string
+	recentLast       bool
+	rank             string
+	largeServiceArea string
+	partyCapacity    int
+	ktaiCoupon       int
+	responseType     string
+	order            int
+	start            int
+	count            int
+
+	// without holds the --no-<amenity> flags, keyed by amenity flag name.
+	without map[string]*bool
+
+	params       api.GourmetSearchParams
+	filters      []filter.Func
+	participants []participant
+	// favIDs are the favorites kept by --favorites-only or --tag.
+	favIDs []string
+	// recent maps shops visited within --avoid-recent to their last visit,
+	// when --recent-last lists them last instead of excluding them.
+	recent map[string]string
+	// profile is the --rank preference profile; search fills scores.
+	profile *rank.Profile
+	scores  map[string]rank.Score
+}
+
+// clientFlags are the search flags applied to fetched shops rather than
+// sent to the API, besides --no-<amenity>.
+var clientFlags = []string{"max-walk", "station", "where", "exclude-genre", "exclude-area", "exclude-keyword", "max-pages", "favorites-only", "tag", "avoid-recent", "recent-last"}
+
+// participant is one --from starting point of a meet-in-the-middle search.
+type participant struct {
+	Label string `json:"label"`
+	geo.Point
+}
+
+// register adds the search flags to f.
+func (sf *searchFlags) register(f *pflag.FlagSet) {
+	sf.without = map[string]*bool{}
+
+	// Text search
+	f.StringVar(&sf.keyword, "keyword", "", "free text search")
+	f.StringVar(&sf.name, "name", "", "shop name (partial match)")
+	f.StringVar(&sf.nameKana, "name-kana", "", "shop name in kana")
+	f.StringVar(&sf.nameAny, "name-any", "", "shop name or kana")
+	f.StringVar(&sf.tel, "tel", "", "phone number (digits only)")
+	f.StringVar(&sf.address, "address", "", "address (partial match)")
+
+	// Location
+	f.Float64Var(&sf.lat, "lat", 0, "latitude")
+	f.Float64Var(&sf.lng, "lng", 0, "longitude")
+	f.IntVar(&sf.rangeCode, "range", 0, "search range: 1=300m 2=500m 3=1km 4=2km 5=3km")
+	f.StringVar(&sf.datum, "datum", "", "geodetic system: world or tokyo")
+	f.StringVar(&sf.near, "near", "", "station or landmark name (kanji, kana or romaji) instead of --lat/--lng")
+	f.StringArrayVar(&sf.from, "from", nil, "participant starting point as lat,lng or station name (repeatable); searches around the meeting point")
+	f.StringVar(&sf.meet, "meet", "centroid", "meeting point for --from: centroid or minimax")
+
+	// Client-side filters (applied to the fetched page)
+	f.IntVar(&sf.maxWalk, "max-walk", 0, "max walking minutes from a station, parsed from the access text")
+	f.StringVar(&sf.station, "station", "", "only shops with access from this station (kanji or romaji)")
+	f.StringVar(&sf.where, "where", "", "filter expression over shop fields, e.g. 'capacity >= 40 and not karaoke'")
+	f.StringSliceVar(&sf.excludeGenre, "exclude-genre", nil, "exclude genre/sub-genre codes or names")
+	f.StringSliceVar(&sf.excludeArea, "exclude-area", nil, "exclude area codes or names (any level)")
+	f.StringSliceVar(&sf.excludeKeyword, "exclude-keyword", nil, "exclude shops mentioning these words")
+	f.BoolVar(&sf.favoritesOnly, "favorites-only", false, "only shops in the favorites (see hpp fav)")
+	f.StringSliceVar(&sf.tags, "tag", nil, "only favorites with one of these tags (implies --favorites-only)")
+	f.StringVar(&sf.avoidRecent, "avoid-recent", "", "exclude shops visited within this window, e.g. 14d (see hpp visit)")
+	f.BoolVar(&sf.recentLast, "recent-last", false, "with --avoid-recent, list recently visited shops last instead of excluding them")
+	f.IntVar(&sf.maxPages, "max-pages", 5, "max pages of 100 to scan when client-side filters are set (at least 1)")
+
+	// Area filters
+	f.StringVar(&sf.largeServiceArea, "large-service-area", "", "large service area code")
+	f.StringSliceVar(&sf.params.ServiceArea, "service-area", nil, "service area codes")
+	f.StringSliceVar(&sf.params.LargeArea, "area", nil, "large area codes")
+	f.StringSliceVar(&sf.params.MiddleArea, "middle-area", nil, "middle area codes")
+	f.StringSliceVar(&sf.params.SmallArea, "small-area", nil, "small area codes")
+
+	// Category
+	f.StringSliceVar(&sf.params.Genre, "genre", nil, "genre codes")
+	f.StringSliceVar(&sf.params.Budget, "budget", nil, "budget codes")
+	f.StringSliceVar(&sf.params.CreditCardFilter, "credit-card", nil, "credit card codes")
+	f.StringSliceVar(&sf.params.Special, "special", nil, "special codes (AND)")
+	f.StringSliceVar(&sf.params.SpecialOr, "special-or", nil, "special codes (OR)")
+	f.StringSliceVar(&sf.params.SpecialCategory, "special-category", nil, "special category codes (AND)")
+	f.StringSliceVar(&sf.params.SpecialCategoryOr, "special-category-or", nil, "special category codes (OR)")
+
+	// Capacity
+	f.IntVar(&sf.partyCapacity, "party-capacity", 0, "min banquet capacity")
+
+	// Boolean filters
+	f.BoolVar(&sf.params.WiFi, "wifi", false, "has WiFi")
+	f.BoolVar(&sf.params.Wedding, "wedding", false, "wedding/party inquiry")
+	f.BoolVar(&sf.params.Course, "course", false, "has courses")
+	f.BoolVar(&sf.params.FreeDrink, "free-drink", false, "all-you-can-drink")
+	f.BoolVar(&sf.params.FreeFood, "free-food", false, "all-you-can-eat")
+	f.BoolVar(&sf.params.PrivateRoom, "private-room", false, "has private rooms")
+	f.BoolVar(&sf.params.Horigotatsu, "horigotatsu", false, "sunken kotatsu seating")
+	f.BoolVar(&sf.params.Tatami, "tatami", false, "tatami seating")
+	f.BoolVar(&sf.params.Cocktail, "cocktail", false, "cocktail selection")
+	f.BoolVar(&sf.params.Shochu, "shochu", false, "shochu selection")
+	f.BoolVar(&sf.params.Sake, "sake", false, "sake selection")
+	f.BoolVar(&sf.params.Wine, "wine", false, "wine selection")
+	f.BoolVar(&sf.params.Card, "card", false, "accepts cards")
+	f.BoolVar(&sf.params.NonSmoking, "non-smoking", false, "non-smoking seats")
+	f.BoolVar(&sf.params.Charter, "charter", false, "private rental")
+	f.BoolVar(&sf.params.Ktai, "ktai", false, "mobile phone OK")
+	f.BoolVar(&sf.params.Parking, "parking", false, "has parking")
+	f.BoolVar(&sf.params.BarrierFree, "barrier-free", false, "barrier-free access")
+	f.BoolVar(&sf.params.Sommelier, "sommelier", false, "has sommelier")
+	f.BoolVar(&sf.params.NightView, "night-view", false, "scenic night view")
+	f.BoolVar(&sf.params.OpenAir, "open-air", false, "open-air seating")
+	f.BoolVar(&sf.params.Show, "show", false, "live/show")
+	f.BoolVar(&sf.params.Equipment, "equipment", false, "entertainment equipment")
+	f.BoolVar(&sf.params.Karaoke, "karaoke", false, "has karaoke")
+	f.BoolVar(&sf.params.Band, "band", false, "band performance OK")
+	f.BoolVar(&sf.params.TV, "tv", false, "has TV/projector")
+	f.BoolVar(&sf.params.Lunch, "lunch", false, "lunch service")
+	f.BoolVar(&sf.params.Midnight, "midnight", false, "open after 11pm")
+	f.BoolVar(&sf.params.MidnightMeal, "midnight-meal", false, "food after 11pm")
+	f.BoolVar(&sf.params.English, "english", false, "English menu")
+	f.BoolVar(&sf.params.Pet, "pet", false, "pet allowed")
+	f.BoolVar(&sf.params.Child, "child", false, "children welcome")
+
+	// Negated boolean filters (client-side)
+	for _, a := range api.Amenities {
+		name := amenityFlag(a)
+		sf.without[name] = f.Bool("no-"+name, false, "exclude: "+f.Lookup(name).Usage)
+	}
+
+	// Mobile coupon
+	f.IntVar(&sf.ktaiCoupon, "ktai-coupon", -1, "mobile coupon: 0=with 1=without")
+
+	// Output control
+	f.StringVar(&sf.responseType, "type", "", "response type: lite, credit_card, special")
+	f.IntVar(&sf.order, "order", 0, "sort: 1=name 2=genre 3=area 4=recommended")
+	f.IntVar(&sf.start, "start", 0, "result start position")
+	f.IntVar(&sf.count, "count", 0, "results per page (max 100)")
+}
+
+// registerRank adds --rank to f. It is separate from register because only
+// commands printing a result list use it.
+func (sf *searchFlags) registerRank(f *pflag.FlagSet) {
+	f.StringVar(&sf.rank, "rank", "", `sort by score from this preference profile in the config ("default" works without one)`)
+}
+
+// parse builds the search from the flags set on f.
+func (sf *searchFlags) parse(f *pflag.FlagSet) error {
+	if f.Changed("keyword") {
+		sf.params.Keyword = &sf.keyword
+	}
+	if f.Changed("name") {
+		sf.params.Name = &sf.name
+	}
+	if f.Changed("name-kana") {
+		sf.params.NameKana = &sf.nameKana
+	}
+	if f.Changed("name-any") {
+		sf.params.NameAny = &sf.nameAny
+	}
+	if f.Changed("tel") {
+		sf.params.Tel = &sf.tel
+	}
+	if f.Changed("address") {
+		sf.params.Address = &sf.address
+	}
+	if f.Changed("lat") {
+		sf.params.Lat = &sf.lat
+	}
+	if f.Changed("lng") {
+		sf.params.Lng = &sf.lng
+	}
+	if f.Changed("range") {
+		sf.params.Range = &sf.rangeCode
+	}
+	if f.Changed("datum") {
+		sf.params.Datum = &sf.datum
+	}
+	if f.Changed("near") {
+		if sf.params.Lat != nil || sf.params.Lng != nil {
+			return fmt.Errorf("--near cannot be combined with --lat/--lng")
+		}
+		p, err := geo.Resolve(sf.near)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Searching near %s (%s) at %.6f,%.6f\n", p.Name, p.Romaji, p.Lat, p.Lng)
+		sf.params.Lat = &p.Lat
+		sf.params.Lng = &p.Lng
+	}
+	if sf.maxPages < 1 {
+		return fmt.Errorf("--max-pages must be at least 1")
+	}
+	if sf.maxWalk > 0 || sf.station != "" {
+		sf.filters = append(sf.filters, filter.Walk(sf.station, sf.maxWalk))
+	}
+	if f.Changed("where") {
+		where, err := filter.Where(sf.where)
+		if err != nil {
+			return fmt.Errorf("--where: %w", err)
+		}
+		sf.filters = append(sf.filters, where)
+	}
+	if len(sf.excludeGenre) > 0 {
+		sf.filters = append(sf.filters, filter.ExcludeGenre(sf.excludeGenre))
+	}
+	if len(sf.excludeArea) > 0 {
+		sf.filters = append(sf.filters, filter.ExcludeArea(sf.excludeArea))
+	}
+	if len(sf.excludeKeyword) > 0 {
+		sf.filters = append(sf.filters, filter.ExcludeKeyword(sf.excludeKeyword))
+	}
+	if sf.favoritesOnly || len(sf.tags) > 0 {
+		ids, err := favoriteIDs(sf.tags)
+		if err != nil {
+			return err
+		}
+		sf.favIDs = ids
+		sf.filters = append(sf.filters, filter.IDs(ids))
+	}
+	if sf.avoidRecent != "" {
+		recent, err := recentVisits(sf.avoidRecent)
+		if err != nil {
+			return fmt.Errorf("--avoid-recent: %w", err)
+		}
+		if sf.recentLast {
+			sf.recent = recent
+		} else if len(recent) > 0 {
+			sf.filters = append(sf.filters, filter.ExcludeIDs(slices.Collect(maps.Keys(recent))))
+		}
+	} else if sf.recentLast {
+		return fmt.Errorf("--recent-last needs --avoid-recent")
+	}
+	for _, a := range api.Amenities {
+		name := amenityFlag(a)
+		if !*sf.without[name] {
+			continue
+		}
+		if f.Changed(name) {
+			return fmt.Errorf("--%s and --no-%s cannot be combined", name, name)
+		}
+		sf.filters = append(sf.filters, filter.Without(a))
+	}
+	if f.Changed("from") {
+		if sf.params.Lat != nil || sf.params.Lng != nil {
+			return fmt.Errorf("--from cannot be combined with --lat/--lng/--near")
+		}
+		var points []geo.Point
+		for _, from := range sf.from {
+			pt, label, err := geo.ParsePoint(from)
+			if err != nil {
+				return fmt.Errorf("--from %s: %w", from, err)
+			}
+			sf.participants = append(sf.participants, participant{Label: label, Point: pt})
+			points = append(points, pt)
+		}
+		var meet geo.Point
+		switch sf.meet {
+		case "centroid":
+			meet = geo.Centroid(points)
+		case "minimax":
+			meet = geo.Minimax(points)
+		default:
+			return fmt.Errorf("--meet must be centroid or minimax, got %q", sf.meet)
+		}
+		fmt.Fprintf(os.Stderr, "Meeting point (%s) for %d participants: %.6f,%.6f\n",
+			sf.meet, len(points), meet.Lat, meet.Lng)
+		sf.params.Lat = &meet.Lat
+		sf.params.Lng = &meet.Lng
+	}
+	if f.Changed("large-service-area") {
+		sf.params.LargeServiceArea = &sf.largeServiceArea
+	}
+	if f.Changed("party-capacity") {
+		sf.params.PartyCapacity = &sf.partyCapacity
+	}
+	if f.Changed("ktai-coupon") {
+		sf.params.KtaiCoupon = &sf.ktaiCoupon
+	}
+	if err := sf.resolveNames(); err != nil {
+		return err
+	}
+	if f.Changed("type") {
+		sf.params.Type = &sf.responseType
+	}
+	if f.Changed("order") {
+		sf.params.Order = &sf.order
+	}
+	if f.Changed("start") {
+		sf.params.Start = &sf.start
+	}
+	if f.Changed("count") {
+		sf.params.Count = &sf.count
+	}
+	if sf.rank != "" {
+		if len(sf.participants) > 0 {
+			return fmt.Errorf("--rank cannot be combined with --from")
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if sf.profile, err = cfg.RankProfile(sf.rank); err != nil {
+			return fmt.Errorf("--rank: %w", err)
+		}
+		if sf.profile.Distance != 0 && (sf.params.Lat == nil || sf.params.Lng == nil) {
+			fmt.Fprintf(os.Stderr, "warning: rank profile %s weighs distance, but the search has no --lat/--lng or --near\n", sf.rank)
+		}
+	}
+	return nil
+}
+
+// amenityFlag returns the search flag name for an amenity, e.g. "free-drink".
+func amenityFlag(a api.Amenity) string {
+	return strings.ReplaceAll(a.Name, "_", "-")
+}
+
+// resolveNames replaces names given to code flags (e.g. --genre 居酒屋)
+// with their codes, looked up in the snapshot saved by hpp sync. The
+// snapshot is only read when some value is not a code.
+func (sf *searchFlags) resolveNames() error {
+	var largeServiceArea []string
+	if sf.params.LargeServiceArea != nil {
+		largeServiceArea = []string{*sf.params.LargeServiceArea}
+	}
+	fields := []struct {
+		flag, table string
+		values      *[]string
+	}{
+		{"large-service-area", "large_service_area", &largeServiceArea},
+		{"service-area", "service_area", &sf.params.ServiceArea},
+		{"area", "large_area", &sf.params.LargeArea},
+		{"middle-area", "middle_area", &sf.params.MiddleArea},
+		{"small-area", "small_area", &sf.params.SmallArea},
+		{"genre", "genre", &sf.params.Genre},
+		{"budget", "budget", &sf.params.Budget},
+		{"credit-card", "credit_card", &sf.params.CreditCardFilter},
+		{"special", "special", &sf.params.Special},
+		{"special-or", "special", &sf.params.SpecialOr},
+		{"special-category", "special_category", &sf.params.SpecialCategory},
+		{"special-category-or", "special_category", &sf.params.SpecialCategoryOr},
+	}
+
+	var snap *master.Snapshot
+	for _, f := range fields {
+		for i, v := range *f.values {
+			if master.IsCode(v) {
+				continue
+			}
+			if snap == nil {
+				var err error
+				if snap, err = latestSnapshot(); err != nil {
+					return fmt.Errorf("--%s %s: names need master data: %w", f.flag, v, err)
+				}
+			}
+			code, err := snap.Resolve(f.table, v)
+			if err != nil {
+				return fmt.Errorf("--%s: %w", f.flag, err)
+			}
+			fmt.Fprintf(os.Stderr, "Resolved --%s %s to %s\n", f.flag, v, code)
+			(*f.values)[i] = code
+		}
+	}
+	if len(largeServiceArea) > 0 {
+		sf.params.LargeServiceArea = &largeServiceArea[0]
+	}
+	return nil
+}
+
+// clientArgs returns the client-side flags set on f as arguments, so they
+// can be stored and parsed again later.
+func (sf *searchFlags) clientArgs(f *pflag.FlagSet) []string {
+	var args []string
+	f.Visit(func(fl *pflag.Flag) {
+		amenity, negated := strings.CutPrefix(fl.Name, "no-")
+		if !slices.Contains(clientFlags, fl.Name) && !(negated && sf.without[amenity] != nil) {
+			return
+		}
+		if sv, ok := fl.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				args = append(args, "--"+fl.Name+"="+v)
+			}
+			return
+		}
+		if fl.Value.Type() == "bool" && fl.Value.String() == "true" {
+			args = append(args, "--"+fl.Name)
+			return
+		}
+		args = append(args, "--"+fl.Name+"="+fl.Value.String())
+	})
+	return args
+}
+
+// search runs the search, scanning pages when client-side filters are set.
+func (sf *searchFlags) search(client *api.Client) (*api.GourmetResults, error) {
+	p := sf.params
+	if len(sf.favIDs) > 0 && len(sf.favIDs) <= api.MaxIDsPerRequest && len(p.ID) == 0 {
+		// Few enough favorites to ask for them by ID instead of scanning.
+		p.ID = sf.favIDs
+	}
+	var results *api.GourmetResults
+	if len(sf.filters) > 0 {
+		var err error
+		if results, err = sf.collect(client, p); err != nil {
+			return nil, err
+		}
+	} else {
+		var resp api.GourmetResponse
+		if err := client.Get("/gourmet/v1/", p, &resp); err != nil {
+			return nil, err
+		}
+		for i := range resp.Results.Shops {
+			resp.Results.Shops[i].ParseAccess()
+		}
+		results = &resp.Results
+	}
+	if sf.profile != nil {
+		var origin *geo.Point
+		if p.Lat != nil && p.Lng != nil {
+			origin = &geo.Point{Lat: *p.Lat, Lng: *p.Lng}
+		}
+		sf.scores = sf.profile.Rank(results.Shops, origin)
+	}
+	if len(sf.recent) > 0 {
+		sf.rankRecentLast(results.Shops)
+	}
+	return results, nil
+}
+
+// rankRecentLast moves recently visited shops to the end, least recently
+// visited first, keeping the API order otherwise.
+func (sf *searchFlags) rankRecentLast(shops []api.Shop) {
+	slices.SortStableFunc(shops, func(a, b api.Shop) int {
+		return strings.Compare(sf.recent[a.ID], sf.recent[b.ID])
+	})
+	n := 0
+	for _, s := range shops {
+		if sf.recent[s.ID] != "" {
+			n++
+		}
+	}
+	if n > 0 {
+		fmt.Fprintf(os.Stderr, "Listed %d recently visited shops last\n", n)
+	}
+}
+
+// collect scans result pages until --count shops pass the
+// client-side filters, then reports counts adjusted for the filtering.
+func (sf *searchFlags) collect(client *api.Client, p api.GourmetSearchParams) (*api.GourmetResults, error) {
+	want := 10 // API default page size
+	if p.Count != nil {
+		want = *p.Count
+	}
+	res, err := filter.Collect(client, p, want, sf.maxPages, sf.filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	approx := "~"
+	if res.Exhausted {
+		approx = ""
+	}
+	fmt.Fprintf(os.Stderr, "Client-side filters matched %d of %d scanned shops (%s%d of %d results)\n",
+		len(res.Shops), res.Scanned, approx, res.Estimate(), res.Available)
+	if !res.Exhausted {
+		if len(res.Shops) < want {
+			fmt.Fprintf(os.Stderr, "warning: stopped after %d pages; matches beyond result %d were not scanned (raise --max-pages)\n",
+				sf.maxPages, res.NextStart-1)
+		}
+		fmt.Fprintf(os.Stderr, "Next page: --start %d (positions count unfiltered results)\n", res.NextStart)
+	}
+
+	start := 1
+	if p.Start != nil {
+		start = *p.Start
+	}
+	return &api.GourmetResults{
+		ResultsAvailable: res.Estimate(),
+		ResultsReturned:  strconv.Itoa(len(res.Shops)),
+		ResultsStart:     start,
+		Shops:            res.Shops,
+	}, nil
+}
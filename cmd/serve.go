@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr      string
+	serveCacheTTL  time.Duration
+	serveCacheSize int
+	serveRate      float64
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local REST API server",
+	Long: `Serve a JSON REST API backed by the HotPepper API, so other apps need no key.
+
+Responses are normalized (numbers as numbers, amenities as booleans, parsed
+access routes). Upstream calls are cached, rate-limited and identical
+concurrent requests share a single call.
+
+Endpoints:
+  GET /shops                  gourmet search; query parameters as in the API (keyword, lat, genre, wifi=1, ...)
+  GET /shops/{id}             one shop
+  GET /genres                 genres
+  GET /budgets                budget bands
+  GET /credit-cards           credit cards
+  GET /service-areas          service areas
+  GET /service-areas/large    large service areas
+  GET /areas/large            large areas (large_area, keyword)
+  GET /areas/middle           middle areas (middle_area, large_area, keyword, start, count)
+  GET /areas/small            small areas (small_area, middle_area, keyword, start, count)
+  GET /specials               specials (special, special_category)
+  GET /special-categories     special categories (special_category)
+  GET /healthz                health check`,
+	Example: `  hpp serve
+  hpp serve --addr 127.0.0.1:9000 --cache-ttl 10m --rate 2
+  curl 'localhost:8080/shops?keyword=ramen&middle_area=Y005&count=5'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("rate") {
+			if err := setRate(client, serveRate); err != nil {
+				return err
+			}
+		}
+		srv := &http.Server{
+			Addr: serveAddr,
+			Handler: server.New(client, server.Options{
+				CacheTTL:  serveCacheTTL,
+				CacheSize: serveCacheSize,
+				Logger:    logger,
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		errc := make(chan error, 1)
+		go func() { errc <- srv.ListenAndServe() }()
+		fmt.Fprintf(os.Stderr, "Listening on %s\n", serveAddr)
+
+		select {
+		case err := <-errc:
+			return err
+		case <-ctx.Done():
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("shutting down: %w", err)
+		}
+		if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	f := serveCmd.Flags()
+	f.StringVar(&serveAddr, "addr", ":8080", "listen address")
+	f.DurationVar(&serveCacheTTL, "cache-ttl", 5*time.Minute, "how long to reuse upstream results (0 disables caching)")
+	f.IntVar(&serveCacheSize, "cache-size", 1000, "max cached upstream results")
+	f.Float64Var(&serveRate, "rate", 0, "max upstream requests per second, 0 for unlimited (default: the hpp quota set rate)")
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/jackchuka/hpp/internal/api"
@@ -18,13 +17,12 @@ var serviceAreaLargeCmd = &cobra.Command{
 	Use:   "large",
 	Short: "List large service areas",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.LargeServiceAreaResponse
-		if err := client.Get("/large_service_area/v1/", nil, &resp); err != nil {
+		if err := src.Get("/large_service_area/v1/", nil, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -43,13 +41,12 @@ var serviceAreaListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List service areas",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.ServiceAreaResponse
-		if err := client.Get("/service_area/v1/", nil, &resp); err != nil {
+		if err := src.Get("/service_area/v1/", nil, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -40,11 +40,10 @@ var shopCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		client, err := newClient()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.ShopSearchResponse
 		if err := client.Get("/shop/v1/", shopParams, &resp); err != nil {
 			return err
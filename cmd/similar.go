@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/filter"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/similar"
+	"github.com/spf13/cobra"
+)
+
+var (
+	similarRange        int
+	similarCount        int
+	similarBudgetSpread int
+	similarExcludeChain bool
+)
+
+// similarResponse is the JSON output of hpp similar.
+type similarResponse struct {
+	Reference api.Shop        `json:"reference"`
+	Matches   []similar.Match `json:"matches"`
+}
+
+var similarCmd = &cobra.Command{
+	Use:   "similar <shop-id>",
+	Short: "Find shops similar to a shop",
+	Long: `Find alternatives to a shop: shops of the same genre in its middle area and
+within --range of it, in the same or a neighboring budget band. They are
+ranked by similarity to the shop: amenities in common, average budget,
+sub-genre, capacity, area and distance.
+
+With --exclude-chain, other branches of the same chain (same name apart
+from a "…店" branch suffix) are left out.
+
+Neighboring areas are reached through the --range radius around the shop
+rather than by middle area; raise --range to reach further. Each search
+reads up to 300 shops.`,
+	Example: `  hpp similar J001234567 --format table
+  hpp similar J001234567 --exclude-chain --range 3 --count 5`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if similarRange < 1 || similarRange > 5 {
+			return fmt.Errorf("--range must be from 1 to 5")
+		}
+		if similarCount < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+		if similarBudgetSpread < 0 {
+			return fmt.Errorf("--budget-spread must not be negative")
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		shops, missing, err := client.ShopsByID(args)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("shop not found: %s", args[0])
+		}
+		ref := shops[0]
+		if ref.Genre.Code == "" {
+			return fmt.Errorf("%s has no genre to match", ref.ID)
+		}
+
+		cands, err := similarCandidates(client, &ref)
+		if err != nil {
+			return err
+		}
+		if similarExcludeChain {
+			chain := similar.ChainKey(ref.Name)
+			var kept []api.Shop
+			for _, s := range cands {
+				if similar.ChainKey(s.Name) != chain {
+					kept = append(kept, s)
+				}
+			}
+			cands = kept
+		}
+		matches := similar.Rank(&ref, cands)
+		if len(matches) > similarCount {
+			matches = matches[:similarCount]
+		}
+
+		if outputFormat == "json" {
+			if matches == nil {
+				matches = []similar.Match{}
+			}
+			return output.WriteJSON(os.Stdout, similarResponse{Reference: ref, Matches: matches})
+		}
+		fmt.Fprintf(os.Stderr, "Shops like %s (%s, %s, %s)\n\n", ref.Name, ref.Genre.Name, ref.Budget.Name, ref.MiddleArea.Name)
+		tw := output.NewTableWriter(os.Stdout, []string{"SIMILARITY", "NAME", "GENRE", "AREA", "BUDGET", "DISTANCE", "WHY", "URL"})
+		for _, m := range matches {
+			tw.Row(fmt.Sprintf("%.0f%%", m.Similarity*100), m.Shop.Name, m.Shop.Genre.Name, m.Shop.MiddleArea.Name,
+				m.Shop.Budget.Average, geo.FormatDistance(float64(m.Distance)), strings.Join(m.Reasons, ", "), m.Shop.URLs.PC)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+// similarMaxPages is the most result pages fetched per candidate search.
+const similarMaxPages = 3
+
+// similarCandidates fetches shops of ref's genre in its middle area and
+// around it, without duplicates. When ref's budget band is known, only
+// shops within --budget-spread bands of it are asked for.
+func similarCandidates(client *api.Client, ref *api.Shop) ([]api.Shop, error) {
+	bands, err := nearbyBudgets(client, ref)
+	if err != nil {
+		return nil, err
+	}
+	var searches []api.GourmetSearchParams
+	for _, budget := range bands {
+		searches = append(searches, api.GourmetSearchParams{Genre: []string{ref.Genre.Code}, Budget: budget, Lat: &ref.Lat, Lng: &ref.Lng, Range: &similarRange})
+		if ref.MiddleArea.Code != "" {
+			searches = append(searches, api.GourmetSearchParams{Genre: []string{ref.Genre.Code}, Budget: budget, MiddleArea: []string{ref.MiddleArea.Code}})
+		}
+	}
+
+	seen := map[string]bool{}
+	var shops []api.Shop
+	for _, p := range searches {
+		res, err := filter.Collect(client, p, similarMaxPages*api.MaxGourmetCount, similarMaxPages)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range res.Shops {
+			if !seen[s.ID] {
+				seen[s.ID] = true
+				shops = append(shops, s)
+			}
+		}
+	}
+	return shops, nil
+}
+
+// nearbyBudgets returns the budget codes within --budget-spread bands of
+// ref's, split into groups the API accepts in one request. It returns a
+// single empty group, searching every budget, when ref's band is unknown.
+func nearbyBudgets(client *api.Client, ref *api.Shop) ([][]string, error) {
+	var r api.BudgetResponse
+	if err := client.Get("/budget/v1/", struct{}{}, &r); err != nil {
+		return nil, err
+	}
+	bands := make([]api.Budget, len(r.Results.Budgets))
+	for i, b := range r.Results.Budgets {
+		bands[i] = api.Budget{Code: b.Code, Name: b.Name}
+	}
+	codes := similar.NearbyBands(bands, ref.Budget.Code, similarBudgetSpread)
+	if codes == nil {
+		fmt.Fprintf(os.Stderr, "warning: %s has no known budget band; not filtering by budget\n", ref.ID)
+		return [][]string{nil}, nil
+	}
+	return slices.Collect(slices.Chunk(codes, api.MaxBudgetCodes)), nil
+}
+
+func init() {
+	rootCmd.AddCommand(similarCmd)
+	similarCmd.Flags().IntVar(&similarRange, "range", 4, "also search around the shop: 1=300m 2=500m 3=1km 4=2km 5=3km")
+	similarCmd.Flags().IntVar(&similarCount, "count", 10, "number of shops to list")
+	similarCmd.Flags().IntVar(&similarBudgetSpread, "budget-spread", 1, "budget bands above or below the shop's to include")
+	similarCmd.Flags().BoolVar(&similarExcludeChain, "exclude-chain", false, "leave out other branches of the same chain")
+}
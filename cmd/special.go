@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/jackchuka/hpp/internal/api"
@@ -22,13 +21,12 @@ var specialListCmd = &cobra.Command{
 	Example: `  hpp special list
   hpp special list --category SPC0`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.SpecialResponse
-		if err := client.Get("/special/v1/", specialParams, &resp); err != nil {
+		if err := src.Get("/special/v1/", specialParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
@@ -49,13 +47,12 @@ var specialCategoryCmd = &cobra.Command{
 	Use:   "category",
 	Short: "List special categories",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		apiKey := os.Getenv("HOTPEPPER_API_KEY")
-		if apiKey == "" {
-			return fmt.Errorf("HOTPEPPER_API_KEY environment variable is required")
+		src, err := masterSource()
+		if err != nil {
+			return err
 		}
-		client := api.NewClient(apiKey)
 		var resp api.SpecialCategoryResponse
-		if err := client.Get("/special_category/v1/", specialCategoryParams, &resp); err != nil {
+		if err := src.Get("/special_category/v1/", specialCategoryParams, &resp); err != nil {
 			return err
 		}
 		if outputFormat == "json" {
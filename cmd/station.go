@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var stationCmd = &cobra.Command{
+	Use:   "station",
+	Short: "Browse the built-in station and landmark dataset",
+	Long:  "Browse the offline dataset of stations and landmarks used to resolve --near.",
+}
+
+var (
+	stationKind  string
+	stationLimit int
+)
+
+var stationSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search stations and landmarks by kanji, kana or romaji",
+	Example: `  hpp station search 浜松町
+  hpp station search shinbashi
+  hpp station search --kind landmark`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var query string
+		if len(args) > 0 {
+			query = args[0]
+		}
+		var matches []geo.Match
+		for _, m := range geo.Search(query) {
+			if stationKind != "" && m.Kind != stationKind {
+				continue
+			}
+			matches = append(matches, m)
+		}
+		if stationLimit > 0 && len(matches) > stationLimit {
+			matches = matches[:stationLimit]
+		}
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, matches)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"NAME", "KANA", "ROMAJI", "KIND", "LINES", "LAT", "LNG"})
+		for _, m := range matches {
+			tw.Row(m.Name, m.Kana, m.Romaji, m.Kind, strings.Join(m.Lines, ","),
+				fmt.Sprintf("%.6f", m.Lat), fmt.Sprintf("%.6f", m.Lng))
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stationCmd)
+	stationCmd.AddCommand(stationSearchCmd)
+
+	stationSearchCmd.Flags().StringVar(&stationKind, "kind", "", "filter by kind: station or landmark")
+	stationSearchCmd.Flags().IntVar(&stationLimit, "limit", 0, "max results (0 = all)")
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/filter"
+	"github.com/jackchuka/hpp/internal/master"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsOpts      searchFlags
+	statsFacets    []string
+	statsAPICounts bool
+)
+
+// statsResponse is the JSON output of hpp stats. Counted is the number of
+// shops aggregated when results are scanned.
+type statsResponse struct {
+	ResultsAvailable int           `json:"results_available"`
+	Source           string        `json:"source"` // "scan" or "api"
+	Counted          int           `json:"counted,omitempty"`
+	Facets           []stats.Facet `json:"facets"`
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Count matching shops by genre, budget, area, station and amenity",
+	Long: `Count the shops matching a search per genre, sub-genre, budget, middle
+and small area, nearest station and amenity. Takes the same flags as
+hpp search.
+
+By default, result pages are fetched (up to --max-pages pages of 100) and the
+shops aggregated, so client-side filters apply. With --api-counts, each
+facet value is instead counted with one count-only request reading the API's
+results_available. That is faster for large result sets, but only works for
+genre, budget, areas and amenities, and needs --area or --middle-area for
+the area facets.`,
+	Example: `  hpp stats --middle-area Y005 --format table
+  hpp stats --keyword 新橋 --facet genre,budget --max-pages 10
+  hpp stats --area Z011 --api-counts --facet genre,middle_area`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		for _, f := range statsFacets {
+			if !slices.Contains(stats.Facets, f) {
+				return fmt.Errorf("--facet: unknown facet %q (want %s)", f, strings.Join(stats.Facets, ", "))
+			}
+		}
+		return statsOpts.parse(cmd.Flags())
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		var resp *statsResponse
+		if statsAPICounts {
+			resp, err = statsFromAPI(client, cmd.Flags().Changed("facet"))
+		} else {
+			resp, err = statsFromScan(client)
+		}
+		if err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, resp)
+		}
+		if resp.Source == "scan" {
+			fmt.Fprintf(os.Stderr, "Counted %d of %d results\n\n", resp.Counted, resp.ResultsAvailable)
+		} else {
+			fmt.Fprintf(os.Stderr, "Counted by the API out of %d results\n\n", resp.ResultsAvailable)
+		}
+		for i, f := range resp.Facets {
+			if i > 0 {
+				fmt.Println()
+			}
+			tw := output.NewTableWriter(os.Stdout, []string{strings.ToUpper(f.Name), "CODE", "SHOPS", "SHARE"})
+			for _, c := range f.Counts {
+				share := ""
+				if f.Total > 0 {
+					share = fmt.Sprintf("%.1f%%", float64(c.Count)*100/float64(f.Total))
+				}
+				tw.Row(c.Name, c.Code, strconv.Itoa(c.Count), share)
+			}
+			tw.Flush()
+		}
+		return nil
+	},
+}
+
+func statsFromScan(client *api.Client) (*statsResponse, error) {
+	want := statsOpts.maxPages * api.MaxGourmetCount
+	res, err := filter.Collect(client, statsOpts.params, want, statsOpts.maxPages, statsOpts.filters...)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Exhausted {
+		fmt.Fprintf(os.Stderr, "warning: stopped after %d pages; counts cover the first %d of %d results (raise --max-pages or use --api-counts)\n",
+			statsOpts.maxPages, res.Scanned, res.Available)
+	}
+	return &statsResponse{
+		ResultsAvailable: res.Available,
+		Source:           "scan",
+		Counted:          len(res.Shops),
+		Facets:           stats.Aggregate(res.Shops, statsFacets),
+	}, nil
+}
+
+// statsFromAPI counts each facet value with a count-only request. Facets
+// the API cannot count are an error when asked for explicitly and skipped
+// otherwise.
+func statsFromAPI(client *api.Client, explicit bool) (*statsResponse, error) {
+	if len(statsOpts.filters) > 0 {
+		return nil, fmt.Errorf("--api-counts cannot be combined with client-side filters")
+	}
+	p := statsOpts.params
+	one := 1
+	p.Count = &one
+	p.Start = nil
+	var base api.GourmetResponse
+	if err := client.Get("/gourmet/v1/", p, &base); err != nil {
+		return nil, err
+	}
+
+	resp := &statsResponse{ResultsAvailable: base.Results.ResultsAvailable, Source: "api", Facets: []stats.Facet{}}
+	for _, name := range statsFacets {
+		if !slices.Contains(stats.APIFacets, name) {
+			if explicit {
+				return nil, fmt.Errorf("--api-counts cannot count %s (use one of %s)", name, strings.Join(stats.APIFacets, ", "))
+			}
+			continue
+		}
+		candidates, err := facetCandidates(client, name)
+		if err != nil {
+			return nil, err
+		}
+		if candidates == nil {
+			fmt.Fprintf(os.Stderr, "Skipping %s: --api-counts needs %s\n", name, map[string]string{
+				"middle_area": "--area or --middle-area",
+				"small_area":  "--middle-area or --small-area",
+			}[name])
+			continue
+		}
+		f, err := stats.FromAPI(client, statsOpts.params, name, resp.ResultsAvailable, candidates)
+		if err != nil {
+			return nil, err
+		}
+		resp.Facets = append(resp.Facets, *f)
+	}
+	return resp, nil
+}
+
+// facetCandidates returns the values to count for a facet, or nil when the
+// search is too broad to list them.
+func facetCandidates(client *api.Client, facet string) ([]api.CodeName, error) {
+	p := statsOpts.params
+	var out []api.CodeName
+	switch facet {
+	case "genre":
+		var r api.GenreResponse
+		if err := client.Get("/genre/v1/", api.GenreParams{}, &r); err != nil {
+			return nil, err
+		}
+		for _, g := range r.Results.Genres {
+			out = append(out, api.CodeName{Code: g.Code, Name: g.Name})
+		}
+	case "budget":
+		var r api.BudgetResponse
+		if err := client.Get("/budget/v1/", struct{}{}, &r); err != nil {
+			return nil, err
+		}
+		for _, b := range r.Results.Budgets {
+			out = append(out, api.CodeName{Code: b.Code, Name: b.Name})
+		}
+	case "middle_area":
+		if len(p.MiddleArea) == 0 && len(p.LargeArea) == 0 {
+			return nil, nil
+		}
+		areas, err := master.MiddleAreas(client, api.MiddleAreaParams{MiddleArea: p.MiddleArea, LargeArea: p.LargeArea})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range areas {
+			out = append(out, api.CodeName{Code: a.Code, Name: a.Name})
+		}
+	case "small_area":
+		if len(p.SmallArea) == 0 && len(p.MiddleArea) == 0 {
+			return nil, nil
+		}
+		areas, err := master.SmallAreas(client, api.SmallAreaParams{SmallArea: p.SmallArea, MiddleArea: p.MiddleArea})
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range areas {
+			out = append(out, api.CodeName{Code: a.Code, Name: a.Name})
+		}
+	case "amenity":
+		for _, a := range api.Amenities {
+			out = append(out, api.CodeName{Code: a.Name, Name: a.Name})
+		}
+	}
+	return out, nil
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsOpts.register(statsCmd.Flags())
+	statsCmd.Flags().StringSliceVar(&statsFacets, "facet", stats.Facets, "facets to count: "+strings.Join(stats.Facets, ", "))
+	statsCmd.Flags().BoolVar(&statsAPICounts, "api-counts", false, "count each facet value with a count-only API request instead of scanning results")
+}
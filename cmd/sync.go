@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jackchuka/hpp/internal/master"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncKeep      int
+	masterOffline bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download master data for offline use",
+	Long: `Download every master table (genres, budgets, credit cards, specials, special
+categories, service areas and all area levels) into a local snapshot. Master
+commands then accept --offline, and search flags taking codes also accept
+names (e.g. --genre 居酒屋 --middle-area 浜松町).
+
+Snapshots are kept as timestamped files in the cache directory; older ones
+remain available for comparison until pruned by --keep.`,
+	Example: `  hpp sync
+  hpp genre --offline
+  hpp area middle --large-area Z011 --offline`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		store, err := master.DefaultStore()
+		if err != nil {
+			return err
+		}
+		snap, err := master.Fetch(client)
+		if err != nil {
+			return err
+		}
+		path, err := store.Save(snap)
+		if err != nil {
+			return err
+		}
+		if err := store.Prune(syncKeep); err != nil {
+			return err
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, map[string]any{
+				"path":        path,
+				"fetched_at":  snap.FetchedAt,
+				"api_version": snap.APIVersion,
+				"tables":      snap.Tables(),
+			})
+		}
+		fmt.Fprintf(os.Stderr, "Saved snapshot %s (API version %s)\n\n", path, snap.APIVersion)
+		tw := output.NewTableWriter(os.Stdout, []string{"TABLE", "COUNT"})
+		for _, t := range snap.Tables() {
+			tw.Row(t.Name, strconv.Itoa(t.Count))
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+// masterGetter answers master endpoint requests; it is implemented by both
+// api.Client and master.Snapshot.
+type masterGetter interface {
+	Get(path string, params, out any) error
+}
+
+// masterSource returns the latest synced snapshot with --offline, and an
+// API client otherwise.
+func masterSource() (masterGetter, error) {
+	if !masterOffline {
+		return newClient()
+	}
+	return latestSnapshot()
+}
+
+func latestSnapshot() (*master.Snapshot, error) {
+	store, err := master.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := store.Latest()
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("using master snapshot", "dir", store.Dir, "fetched_at", snap.FetchedAt)
+	return snap, nil
+}
+
+// addOfflineFlag adds --offline to a master command and its subcommands.
+func addOfflineFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&masterOffline, "offline", false, "read from the snapshot saved by hpp sync instead of the API")
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().IntVar(&syncKeep, "keep", 5, "number of snapshots to keep")
+
+	for _, cmd := range []*cobra.Command{genreCmd, budgetCmd, creditcardCmd, specialCmd, serviceAreaCmd, areaCmd} {
+		addOfflineFlag(cmd)
+	}
+}
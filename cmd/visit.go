@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/config"
+	"github.com/jackchuka/hpp/internal/output"
+	"github.com/jackchuka/hpp/internal/visit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	visitDate   string
+	visitsSince string
+)
+
+// visitEntry is one line of hpp visits output.
+type visitEntry struct {
+	Date  string `json:"date"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Genre string `json:"genre"`
+}
+
+var visitCmd = &cobra.Command{
+	Use:   "visit <shop-id>...",
+	Short: "Record a visit to shops",
+	Long: `Record that shops were visited, today or on --date. Visits are stored in
+visits.json in the config directory (e.g. ~/.config/hpp/visits.json).
+
+Search with --avoid-recent to skip shops visited lately, e.g. to rotate
+team lunches.`,
+	Example: `  hpp visit J001234567
+  hpp visit J001234567 --date 2026-10-16
+  hpp search --near 浜松町 --lunch --avoid-recent 14d`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		date := time.Now().Format(visit.DateLayout)
+		if cmd.Flags().Changed("date") {
+			d, err := time.ParseInLocation(visit.DateLayout, visitDate, time.Local)
+			if err != nil {
+				return fmt.Errorf("--date: want YYYY-MM-DD, got %q", visitDate)
+			}
+			if d.After(time.Now()) {
+				return fmt.Errorf("--date: %s is in the future", visitDate)
+			}
+			date = visitDate
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		shops, missing, err := client.ShopsByID(args)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("shops not found: %s", strings.Join(missing, ", "))
+		}
+
+		store, err := visitStore()
+		if err != nil {
+			return err
+		}
+		for _, s := range shops {
+			added, err := store.Add(visit.Visit{ID: s.ID, Date: date})
+			if err != nil {
+				return err
+			}
+			if added {
+				fmt.Fprintf(os.Stderr, "Recorded visit to %s (%s) on %s\n", s.ID, s.Name, date)
+			} else {
+				fmt.Fprintf(os.Stderr, "Visit to %s (%s) on %s was already recorded\n", s.ID, s.Name, date)
+			}
+		}
+		return nil
+	},
+}
+
+var visitsCmd = &cobra.Command{
+	Use:   "visits",
+	Short: "List visited shops",
+	Long: `List recorded visits, most recent first. Shop names are looked up through
+the gourmet search API.`,
+	Example: `  hpp visits --format table
+  hpp visits --since 30d`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := visitStore()
+		if err != nil {
+			return err
+		}
+		list, err := store.List()
+		if err != nil {
+			return err
+		}
+		if visitsSince != "" {
+			window, err := visit.ParseWindow(visitsSince)
+			if err != nil {
+				return fmt.Errorf("--since: %w", err)
+			}
+			since := visit.Since(time.Now(), window)
+			list = slices.DeleteFunc(list, func(v visit.Visit) bool { return v.Date < since })
+		}
+		slices.Reverse(list)
+
+		entries := []visitEntry{}
+		if len(list) > 0 {
+			ids := make([]string, len(list))
+			for i, v := range list {
+				ids[i] = v.ID
+			}
+			client, err := newClient()
+			if err != nil {
+				return err
+			}
+			shops, missing, err := client.ShopsByID(ids)
+			if err != nil {
+				return err
+			}
+			if len(missing) > 0 {
+				fmt.Fprintf(os.Stderr, "warning: shops no longer listed: %s\n", strings.Join(missing, ", "))
+			}
+			byID := make(map[string]int, len(shops))
+			for i, s := range shops {
+				byID[s.ID] = i
+			}
+			for _, v := range list {
+				e := visitEntry{Date: v.Date, ID: v.ID}
+				if i, ok := byID[v.ID]; ok {
+					e.Name, e.Genre = shops[i].Name, shops[i].Genre.Name
+				}
+				entries = append(entries, e)
+			}
+		}
+
+		if outputFormat == "json" {
+			return output.WriteJSON(os.Stdout, entries)
+		}
+		tw := output.NewTableWriter(os.Stdout, []string{"DATE", "ID", "NAME", "GENRE"})
+		for _, e := range entries {
+			tw.Row(e.Date, e.ID, e.Name, e.Genre)
+		}
+		tw.Flush()
+		return nil
+	},
+}
+
+func visitStore() (*visit.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &visit.Store{Path: filepath.Join(dir, "visits.json")}, nil
+}
+
+// recentVisits returns the shops visited within window (e.g. "14d"),
+// mapped to their last visit date.
+func recentVisits(window string) (map[string]string, error) {
+	d, err := visit.ParseWindow(window)
+	if err != nil {
+		return nil, err
+	}
+	store, err := visitStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Recent(visit.Since(time.Now(), d))
+}
+
+func init() {
+	rootCmd.AddCommand(visitCmd, visitsCmd)
+	visitCmd.Flags().StringVar(&visitDate, "date", "", "visit date as YYYY-MM-DD (default today)")
+	visitsCmd.Flags().StringVar(&visitsSince, "since", "", "only visits within this window, e.g. 30d")
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AccessRoute is one "station, exit, walking minutes" route parsed from a
+// shop's free-text access description.
+type AccessRoute struct {
+	Station     string `json:"station,omitempty"`
+	Exit        string `json:"exit,omitempty"`
+	WalkMinutes int    `json:"walk_minutes,omitempty"` // 0 when not stated
+}
+
+var (
+	// 浜松町駅北口より徒歩3分 / 大門駅A6出口徒歩1分 / 新橋駅 徒歩5分
+	accessRouteRe = regexp.MustCompile(`([^\s、,。/／()（）]+?)駅\s*([^\s、,。/／より徒から]*?口)?\s*(?:より|から)?\s*(?:徒歩\s*(?:約)?\s*(\d+)\s*分)?`)
+	walkOnlyRe    = regexp.MustCompile(`徒歩\s*(?:約)?\s*(\d+)\s*分`)
+	// Line and operator names that precede the station, e.g. "JR", "都営",
+	// "地下鉄銀座線", "東京メトロ日比谷線".
+	linePrefixRe = regexp.MustCompile(`^(?:.*線|JR|都営|各線|東京メトロ|地下鉄|私鉄|京急|東急|京王|小田急|西武|東武|京成|相鉄|阪急|阪神|近鉄|南海|京阪|名鉄|西鉄)+`)
+)
+
+var widthFolder = strings.NewReplacer(
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+	"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+	"Ａ", "A", "Ｂ", "B", "Ｃ", "C", "Ｄ", "D", "Ｅ", "E",
+	"Ｊ", "J", "Ｒ", "R", "　", " ",
+)
+
+// ParseAccess extracts station routes from access text such as
+// "JR浜松町駅北口より徒歩3分／都営大門駅A6出口徒歩1分".
+func ParseAccess(text string) []AccessRoute {
+	text = widthFolder.Replace(text)
+	var routes []AccessRoute
+	for _, m := range accessRouteRe.FindAllStringSubmatch(text, -1) {
+		station := linePrefixRe.ReplaceAllString(m[1], "")
+		if station == "" {
+			continue
+		}
+		r := AccessRoute{Station: station, Exit: m[2]}
+		if m[3] != "" {
+			r.WalkMinutes, _ = strconv.Atoi(m[3])
+		}
+		routes = append(routes, r)
+	}
+	if len(routes) == 0 {
+		if m := walkOnlyRe.FindStringSubmatch(text); m != nil {
+			r := AccessRoute{}
+			r.WalkMinutes, _ = strconv.Atoi(m[1])
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
+
+// ParseAccess populates AccessRoutes from Access, falling back to
+// MobileAccess and StationName.
+func (s *Shop) ParseAccess() {
+	routes := ParseAccess(s.Access)
+	if len(routes) == 0 {
+		routes = ParseAccess(s.MobileAccess)
+	}
+	if s.StationName != "" {
+		if len(routes) == 0 {
+			routes = []AccessRoute{{Station: s.StationName}}
+		} else if routes[0].Station == "" {
+			routes[0].Station = s.StationName
+		}
+	}
+	s.AccessRoutes = routes
+}
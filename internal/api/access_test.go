@@ -0,0 +1,53 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccess(t *testing.T) {
+	tests := []struct {
+		text string
+		want []AccessRoute
+	}{
+		{"JR浜松町駅北口より徒歩3分", []AccessRoute{{Station: "浜松町", Exit: "北口", WalkMinutes: 3}}},
+		{"都営大門駅A6出口徒歩1分", []AccessRoute{{Station: "大門", Exit: "A6出口", WalkMinutes: 1}}},
+		{"浜松町駅から徒歩7分", []AccessRoute{{Station: "浜松町", WalkMinutes: 7}}},
+		{"各線新宿駅東口より徒歩約5分", []AccessRoute{{Station: "新宿", Exit: "東口", WalkMinutes: 5}}},
+		{"東京メトロ日比谷線六本木駅3番出口徒歩1分", []AccessRoute{{Station: "六本木", Exit: "3番出口", WalkMinutes: 1}}},
+		{"ＪＲ新橋駅烏森口 徒歩２分", []AccessRoute{{Station: "新橋", Exit: "烏森口", WalkMinutes: 2}}},
+		{"新橋駅から徒歩5分／汐留駅から徒歩3分", []AccessRoute{
+			{Station: "新橋", WalkMinutes: 5},
+			{Station: "汐留", WalkMinutes: 3},
+		}},
+		{"浜松町駅前", []AccessRoute{{Station: "浜松町"}}},
+		{"バス停より徒歩10分", []AccessRoute{{WalkMinutes: 10}}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := ParseAccess(tt.text)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("ParseAccess(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestShopParseAccess_Fallbacks(t *testing.T) {
+	s := Shop{Access: "お店はビル2階", MobileAccess: "浜松町駅徒歩4分"}
+	s.ParseAccess()
+	if len(s.AccessRoutes) != 1 || s.AccessRoutes[0].WalkMinutes != 4 {
+		t.Fatalf("expected MobileAccess fallback, got %+v", s.AccessRoutes)
+	}
+
+	s = Shop{Access: "徒歩2分", StationName: "大門"}
+	s.ParseAccess()
+	if len(s.AccessRoutes) != 1 || s.AccessRoutes[0].Station != "大門" || s.AccessRoutes[0].WalkMinutes != 2 {
+		t.Fatalf("expected StationName to fill station, got %+v", s.AccessRoutes)
+	}
+
+	s = Shop{StationName: "田町"}
+	s.ParseAccess()
+	if len(s.AccessRoutes) != 1 || s.AccessRoutes[0].Station != "田町" {
+		t.Fatalf("expected StationName route, got %+v", s.AccessRoutes)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Amenity describes a yes/no shop feature: its snake_case name as used by
+// the API and how to read it from a Shop.
+type Amenity struct {
+	Name string
+	Shop func(s *Shop) string
+}
+
+// Amenities lists every yes/no feature reported on Shop.
+var Amenities = []Amenity{
+	{"wifi", func(s *Shop) string { return s.WiFi }},
+	{"wedding", func(s *Shop) string { return s.Wedding }},
+	{"course", func(s *Shop) string { return s.Course }},
+	{"free_drink", func(s *Shop) string { return s.FreeDrink }},
+	{"free_food", func(s *Shop) string { return s.FreeFood }},
+	{"private_room", func(s *Shop) string { return s.PrivateRoom }},
+	{"horigotatsu", func(s *Shop) string { return s.Horigotatsu }},
+	{"tatami", func(s *Shop) string { return s.Tatami }},
+	{"cocktail", func(s *Shop) string { return s.Cocktail }},
+	{"shochu", func(s *Shop) string { return s.Shochu }},
+	{"sake", func(s *Shop) string { return s.Sake }},
+	{"wine", func(s *Shop) string { return s.Wine }},
+	{"card", func(s *Shop) string { return s.Card }},
+	{"non_smoking", func(s *Shop) string { return s.NonSmoking }},
+	{"charter", func(s *Shop) string { return s.Charter }},
+	{"ktai", func(s *Shop) string { return s.Ktai }},
+	{"parking", func(s *Shop) string { return s.Parking }},
+	{"barrier_free", func(s *Shop) string { return s.BarrierFree }},
+	{"sommelier", func(s *Shop) string { return s.Sommelier }},
+	{"night_view", func(s *Shop) string { return s.NightView }},
+	{"open_air", func(s *Shop) string { return s.OpenAir }},
+	{"show", func(s *Shop) string { return s.Show }},
+	{"equipment", func(s *Shop) string { return s.Equipment }},
+	{"karaoke", func(s *Shop) string { return s.Karaoke }},
+	{"band", func(s *Shop) string { return s.Band }},
+	{"tv", func(s *Shop) string { return s.TV }},
+	{"lunch", func(s *Shop) string { return s.Lunch }},
+	{"midnight", func(s *Shop) string { return s.Midnight }},
+	{"midnight_meal", func(s *Shop) string { return s.MidnightMeal }},
+	{"english", func(s *Shop) string { return s.English }},
+	{"pet", func(s *Shop) string { return s.Pet }},
+	{"child", func(s *Shop) string { return s.Child }},
+}
+
+// Amenity values meaning "not available": either the whole value, e.g.
+// "なし" or "未確認", or its ending, e.g. "禁煙席なし", "貸切不可",
+// "営業していない" or "全面喫煙可" (for non_smoking).
+var (
+	negativeValues   = []string{"無", "未確認"}
+	negativeSuffixes = []string{"なし", "不可", "ない", "喫煙可"}
+)
+
+// HasAmenity interprets a free-text amenity value such as "あり ：個室あり",
+// "全面禁煙" or "利用不可" as available or not. Empty values are not.
+func HasAmenity(v string) bool {
+	// Only the leading word decides: "あり ：但し土日なし" is available.
+	head, _, _ := strings.Cut(v, "：")
+	head = strings.TrimSpace(head)
+	if i := strings.IndexAny(head, " 　（("); i >= 0 {
+		head = head[:i]
+	}
+	if head == "" || slices.Contains(negativeValues, head) {
+		return false
+	}
+	for _, neg := range negativeSuffixes {
+		if strings.HasSuffix(head, neg) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetAmenity turns on the search filter for the amenity with the given API
+// name, e.g. "free_drink", and reports whether there is one.
+func (p *GourmetSearchParams) SetAmenity(name string) bool {
+	v := reflect.ValueOf(p).Elem()
+	for i := range v.NumField() {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("url"), ",")
+		if tag == name && v.Field(i).Kind() == reflect.Bool {
+			v.Field(i).SetBool(true)
+			return true
+		}
+	}
+	return false
+}
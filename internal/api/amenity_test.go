@@ -0,0 +1,76 @@
+package api
+
+import "testing"
+
+func TestHasAmenity(t *testing.T) {
+	// Values as returned by the HotPepper API.
+	for _, tt := range []struct {
+		v    string
+		want bool
+	}{
+		{"", false},
+		{"あり", true},
+		{"なし", false},
+		{"未確認", false},
+		{"無", false},
+		{"あり ：完全個室", true},
+		{"なし ：座敷席有り", false},
+		{"あり ：土日なし", true},
+		{"あり ：無料Wi-Fiあり", true},
+		{"無料Wi-Fiあり", true},
+		{"全面禁煙", true},
+		{"一部禁煙", true},
+		{"一部禁煙 ：ランチタイム全面禁煙", true},
+		{"禁煙席なし", false},
+		{"全面喫煙可", false},
+		{"利用可", true},
+		{"利用不可", false},
+		{"貸切可 ：50人以上可", true},
+		{"貸切不可", false},
+		{"営業している", true},
+		{"営業していない", false},
+		{"お子様連れ歓迎", true},
+		{"お子様連れOK", true},
+		{"お子様連れ不可", false},
+		{"可", true},
+		{" あり", true},
+		{"　なし ：座敷席有り", false},
+		{"不可", false},
+		{"つながる", true},
+		{"つながらない", false},
+		{"いる", true},
+		{"いない", false},
+		{"なし（要問合せ）", false},
+	} {
+		if got := HasAmenity(tt.v); got != tt.want {
+			t.Errorf("HasAmenity(%q) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestAmenitiesCoverShop(t *testing.T) {
+	s := Shop{Karaoke: "あり", NonSmoking: "全面禁煙"}
+	got := map[string]string{}
+	for _, a := range Amenities {
+		got[a.Name] = a.Shop(&s)
+	}
+	if got["karaoke"] != "あり" || got["non_smoking"] != "全面禁煙" {
+		t.Fatalf("unexpected amenity values: %v", got)
+	}
+}
+
+func TestSetAmenity(t *testing.T) {
+	for _, a := range Amenities {
+		var p GourmetSearchParams
+		if !p.SetAmenity(a.Name) {
+			t.Fatalf("expected a search filter for %s", a.Name)
+		}
+	}
+	var p GourmetSearchParams
+	if !p.SetAmenity("free_drink") || !p.FreeDrink || p.FreeFood {
+		t.Fatalf("expected only free_drink to be set, got %+v", p)
+	}
+	if p.SetAmenity("keyword") || p.SetAmenity("jacuzzi") {
+		t.Fatal("expected non-amenity names to be rejected")
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	yenRe      = regexp.MustCompile(`\d[\d,]*`)
+	dinnerYen  = regexp.MustCompile(`夜\D{0,3}(\d[\d,]*)`)
+	rangeSepRe = regexp.MustCompile(`[～~〜-]`)
+)
+
+func parseYen(s string) int {
+	n, _ := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	return n
+}
+
+// Range returns the yen bounds of the budget band, parsed from Name such as
+// "3001～4000円", "～500円" or "30001円～". An open bound is 0.
+func (b Budget) Range() (lo, hi int) {
+	name := widthFolder.Replace(b.Name)
+	parts := rangeSepRe.Split(name, 2)
+	if len(parts) != 2 {
+		n := parseYen(yenRe.FindString(name))
+		return n, n
+	}
+	return parseYen(yenRe.FindString(parts[0])), parseYen(yenRe.FindString(parts[1]))
+}
+
+// AverageYen returns the typical spend per person. It reads Average (e.g.
+// "2500円", "昼1000円 夜3000円" — dinner wins), falling back to the middle of
+// the budget band. It returns 0 when neither is known.
+func (b Budget) AverageYen() int {
+	avg := widthFolder.Replace(b.Average)
+	if m := dinnerYen.FindStringSubmatch(avg); m != nil {
+		return parseYen(m[1])
+	}
+	if n := parseYen(yenRe.FindString(avg)); n > 0 {
+		return n
+	}
+	lo, hi := b.Range()
+	switch {
+	case lo > 0 && hi > 0:
+		return (lo + hi) / 2
+	default:
+		return max(lo, hi)
+	}
+}
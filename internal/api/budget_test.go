@@ -0,0 +1,42 @@
+package api
+
+import "testing"
+
+func TestBudgetRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		lo, hi int
+	}{
+		{"3001～4000円", 3001, 4000},
+		{"～500円", 0, 500},
+		{"30001円～", 30001, 0},
+		{"１５０１～２０００円", 1501, 2000},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		lo, hi := Budget{Name: tt.name}.Range()
+		if lo != tt.lo || hi != tt.hi {
+			t.Fatalf("Range(%q) = %d,%d want %d,%d", tt.name, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestBudgetAverageYen(t *testing.T) {
+	tests := []struct {
+		b    Budget
+		want int
+	}{
+		{Budget{Average: "2500円"}, 2500},
+		{Budget{Average: "3,000円（通常平均）"}, 3000},
+		{Budget{Average: "昼1000円 夜3500円"}, 3500},
+		{Budget{Average: "ランチ：900円／ディナー：夜 4000円"}, 4000},
+		{Budget{Name: "3001～4000円"}, 3500},
+		{Budget{Name: "～500円"}, 500},
+		{Budget{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.b.AverageYen(); got != tt.want {
+			t.Fatalf("AverageYen(%+v) = %d, want %d", tt.b, got, tt.want)
+		}
+	}
+}
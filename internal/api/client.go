@@ -2,8 +2,15 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -24,6 +31,16 @@ type Client struct {
 	BaseURL    string
 	APIKey     string
 	HTTPClient *http.Client
+	Limiter    *RateLimiter // paces requests; nil for no limit
+	Logger     *slog.Logger // traces requests and retries; nil disables logging
+	Retries    int          // extra attempts after a 429, 5xx or timeout; 0 by default
+	Quota      Quota        // counts requests against usage caps; nil for none
+}
+
+// Quota is consulted before every request attempt. Take counts the attempt
+// and returns an error to refuse it.
+type Quota interface {
+	Take() error
 }
 
 func NewClient(apiKey string) *Client {
@@ -36,6 +53,18 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
+// retryBackoff is the wait before the first retry; it doubles each attempt.
+var retryBackoff = 500 * time.Millisecond
+
+const maxRetryWait = 30 * time.Second
+
+var keyParamRe = regexp.MustCompile(`([?&]key=)[^&]*`)
+
+// RedactKey hides the value of the key parameter in a request URL.
+func RedactKey(rawURL string) string {
+	return keyParamRe.ReplaceAllString(rawURL, "${1}REDACTED")
+}
+
 type errorResponse struct {
 	Results struct {
 		Error []APIError `json:"error"`
@@ -52,24 +81,100 @@ func (c *Client) Get(path string, params interface{}, out interface{}) error {
 	vals.Set("key", c.APIKey)
 	vals.Set("format", "json")
 
-	req, err := http.NewRequest("GET", u+"?"+vals.Encode(), nil)
+	rawURL := u + "?" + vals.Encode()
+	redacted := RedactKey(rawURL)
+	log := c.Logger
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, status, retryAfter, err := c.do(rawURL, redacted, attempt, log)
+		if attempt < c.Retries && retryable(status, err) {
+			wait := retryBackoff << attempt
+			if retryAfter > 0 {
+				wait = retryAfter
+			}
+			wait = min(wait, maxRetryWait)
+			attrs := []any{"url", redacted, "attempt", attempt + 1, "wait", wait}
+			if err != nil {
+				attrs = append(attrs, "error", err)
+			} else {
+				attrs = append(attrs, "status", status)
+			}
+			log.Warn("retrying request", attrs...)
+			time.Sleep(wait)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if status != http.StatusOK {
+			return fmt.Errorf("unexpected status: %d", status)
+		}
+		return decodeResponse(body, out)
+	}
+}
+
+// do sends one attempt and returns the response body and status, with the
+// Retry-After delay the server asked for, if any.
+func (c *Client) do(rawURL, redacted string, attempt int, log *slog.Logger) ([]byte, int, time.Duration, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return nil, 0, 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	if err := c.Limiter.Wait(req.Context()); err != nil {
+		return nil, 0, 0, err
+	}
+	if c.Quota != nil {
+		if err := c.Quota.Take(); err != nil {
+			return nil, 0, 0, err
+		}
 	}
 
+	start := time.Now()
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("making request: %w", err)
+		// Transport errors quote the request URL; keep the key out of logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = RedactKey(urlErr.URL)
+		}
+		if !errors.Is(err, ErrDryRun) {
+			log.Debug("request failed", "url", redacted, "attempt", attempt+1, "duration", time.Since(start), "error", err)
+		}
+		return nil, 0, 0, fmt.Errorf("making request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	log.Debug("request", "url", redacted, "attempt", attempt+1, "status", resp.StatusCode,
+		"bytes", len(body), "duration", time.Since(start))
+	if err != nil {
+		return nil, resp.StatusCode, 0, fmt.Errorf("reading response: %w", err)
+	}
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
 	}
+	return body, resp.StatusCode, retryAfter, nil
+}
+
+// retryable reports whether an attempt that ended with status or err is
+// worth repeating: rate limiting, server errors and timeouts.
+func retryable(status int, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
 
+func decodeResponse(body []byte, out interface{}) error {
 	// Check for API-level errors first
 	var raw json.RawMessage
-	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+	if err := json.Unmarshal(body, &raw); err != nil {
 		return fmt.Errorf("decoding response: %w", err)
 	}
 
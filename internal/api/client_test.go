@@ -1,9 +1,13 @@
 package api
 
 import (
+	"bytes"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClientGet_Success(t *testing.T) {
@@ -57,3 +61,119 @@ func TestClientGet_APIError(t *testing.T) {
 		t.Fatalf("expected code 2000, got %d", apiErr.Code)
 	}
 }
+
+func TestClientGet_TransportErrorHidesKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // refuse connections
+
+	c := NewClient("secret-key")
+	c.BaseURL = srv.URL
+	err := c.Get("/gourmet/v1/", nil, &struct{}{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "secret-key") || !strings.Contains(err.Error(), "key=REDACTED") {
+		t.Fatalf("expected key to be redacted, got %v", err)
+	}
+}
+
+func TestClientGet_RetriesAndLogs(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":{"results_available":1}}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	c := NewClient("secret-key")
+	c.BaseURL = srv.URL
+	c.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c.Retries = 2
+	if err := c.Get("/gourmet/v1/", nil, &struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+	out := logs.String()
+	if strings.Contains(out, "secret-key") || !strings.Contains(out, "key=REDACTED") {
+		t.Fatalf("expected redacted URLs in logs, got %s", out)
+	}
+	for _, want := range []string{"retrying request", "status=503", "status=200", "bytes=35", "attempt=2", "duration="} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in logs, got %s", want, out)
+		}
+	}
+}
+
+func TestClientGet_GivesUp(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := NewClient("k")
+	c.BaseURL = srv.URL
+	c.Retries = 1
+	err := c.Get("/gourmet/v1/", nil, &struct{}{})
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected status 429 error, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestClientGet_NoRetry(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		status  int
+		retries int
+	}{
+		{"client error", http.StatusBadRequest, 2},
+		{"retries off by default", http.StatusServiceUnavailable, 0},
+	} {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(tt.status)
+		}))
+
+		c := NewClient("k")
+		c.BaseURL = srv.URL
+		c.Retries = tt.retries
+		if err := c.Get("/gourmet/v1/", nil, &struct{}{}); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+		srv.Close()
+		if calls != 1 {
+			t.Fatalf("%s: expected 1 call, got %d", tt.name, calls)
+		}
+	}
+}
+
+func TestRedactKey(t *testing.T) {
+	tests := map[string]string{
+		"https://x/gourmet/v1/?format=json&key=abc&keyword=key": "https://x/gourmet/v1/?format=json&key=REDACTED&keyword=key",
+		"https://x/genre/v1/?key=abc":                           "https://x/genre/v1/?key=REDACTED",
+		"https://x/genre/v1/?monkey=1":                          "https://x/genre/v1/?monkey=1",
+	}
+	for in, want := range tests {
+		if got := RedactKey(in); got != want {
+			t.Fatalf("RedactKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
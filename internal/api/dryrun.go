@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// ErrDryRun is returned for every request sent through a DryRunTransport.
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// DryRunTransport is an http.RoundTripper that prints requests instead of
+// sending them. The API key is never printed. It is safe for concurrent
+// use; each request is printed in one piece.
+type DryRunTransport struct {
+	Out  io.Writer
+	JSON bool // print a JSON object instead of text
+	Curl bool // print a curl command instead of the request
+
+	mu sync.Mutex
+}
+
+// dryRunRequest is the JSON form of a printed request.
+type dryRunRequest struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Params map[string][]string `json:"params"`
+}
+
+func (t *DryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	redacted := RedactKey(req.URL.String())
+	q := req.URL.Query()
+	if q.Has("key") {
+		q.Set("key", "REDACTED")
+	}
+
+	var buf bytes.Buffer
+	switch {
+	case t.Curl:
+		_, _ = fmt.Fprintln(&buf, curlCommand(req))
+	case t.JSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(dryRunRequest{Method: req.Method, URL: redacted, Params: q})
+	default:
+		_, _ = fmt.Fprintf(&buf, "%s %s\n\n", req.Method, redacted)
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "PARAM\tVALUE")
+		for _, name := range slices.Sorted(maps.Keys(q)) {
+			for _, v := range q[name] {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\n", name, v)
+			}
+		}
+		_ = tw.Flush()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.Out.Write(buf.Bytes())
+	return nil, ErrDryRun
+}
+
+// curlCommand returns a shell command repeating req, reading the key from
+// $HOTPEPPER_API_KEY so it can be shared safely.
+func curlCommand(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	q.Set("key", "KEY_PLACEHOLDER")
+	u.RawQuery = q.Encode()
+	s := u.String()
+	// Escape for double quotes, then expand the key from the environment.
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+	s = strings.Replace(s, "KEY_PLACEHOLDER", "${HOTPEPPER_API_KEY}", 1)
+	return fmt.Sprintf(`curl -sS -X %s "%s"`, req.Method, s)
+}
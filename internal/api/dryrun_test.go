@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func dryRunClient(t *DryRunTransport) *Client {
+	c := NewClient("secret-key")
+	c.BaseURL = "https://example.com/hotpepper"
+	c.HTTPClient = &http.Client{Transport: t}
+	return c
+}
+
+func TestDryRun(t *testing.T) {
+	var out strings.Builder
+	c := dryRunClient(&DryRunTransport{Out: &out})
+	keyword := "居酒屋"
+	err := c.Get("/gourmet/v1/", GourmetSearchParams{Keyword: &keyword, Genre: []string{"G001", "G002"}, WiFi: true}, &GourmetResponse{})
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("expected ErrDryRun, got %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "secret-key") {
+		t.Fatalf("output leaks the key:\n%s", got)
+	}
+	for _, want := range []string{
+		"GET https://example.com/hotpepper/gourmet/v1/?format=json&genre=G001&genre=G002&key=REDACTED&keyword=%E5%B1%85%E9%85%92%E5%B1%8B&wifi=1\n",
+		"genre    G001\ngenre    G002\n",
+		"keyword  居酒屋\n",
+		"wifi     1\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestDryRun_JSON(t *testing.T) {
+	var out strings.Builder
+	c := dryRunClient(&DryRunTransport{Out: &out, JSON: true})
+	_ = c.Get("/genre/v1/", GenreParams{Code: []string{"G001"}}, &GenreResponse{})
+	var req dryRunRequest
+	if err := json.Unmarshal([]byte(out.String()), &req); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if req.Method != "GET" || req.Params["code"][0] != "G001" || req.Params["key"][0] != "REDACTED" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+}
+
+func TestDryRun_Curl(t *testing.T) {
+	var out strings.Builder
+	c := dryRunClient(&DryRunTransport{Out: &out, Curl: true})
+	_ = c.Get("/genre/v1/", GenreParams{Code: []string{"G001"}}, &GenreResponse{})
+	want := `curl -sS -X GET "https://example.com/hotpepper/genre/v1/?code=G001&format=json&key=${HOTPEPPER_API_KEY}"` + "\n"
+	if out.String() != want {
+		t.Fatalf("got  %s\nwant %s", out.String(), want)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is an opening period in minutes after midnight of the day it
+// starts. End is past 24:00 (1440) when the shop closes after midnight.
+type Interval struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// HoursRule is the opening periods on a set of weekdays.
+type HoursRule struct {
+	Days      [7]bool // indexed by time.Weekday
+	Intervals []Interval
+}
+
+// Hours is an opening schedule parsed from a shop's free-text hours.
+type Hours []HoursRule
+
+var (
+	// Last-order notes such as "（料理L.O. 翌1:00 ドリンクL.O. 翌1:30）".
+	hoursNoteRe = regexp.MustCompile(`[（(][^）)]*[）)]`)
+	// Either a weekday header such as "月～土、祝前日:" or a time range such
+	// as "17:00～翌2:00".
+	hoursTokenRe = regexp.MustCompile(`([月火水木金土日祝前、・～~〜\s]+)[:：]|(翌朝?)?(\d{1,2})[:：](\d{2})\s*[～~〜-]\s*(翌朝?)?(\d{1,2})[:：](\d{2})`)
+	weekdayChars = []rune("日月火水木金土") // in time.Weekday order
+)
+
+// ParseHours parses opening hours text such as
+// "月～土、祝前日: 17:00～翌2:00 （料理L.O. 翌1:00）日、祝日: 17:00～23:00" or
+// "11:30～14:00、17:00～22:00". Times before a weekday header apply to every
+// day. Holidays are ignored. It returns nil when no times are found.
+func ParseHours(text string) Hours {
+	text = hoursNoteRe.ReplaceAllString(widthFolder.Replace(text), " ")
+	var hours Hours
+	cur := -1
+	for _, m := range hoursTokenRe.FindAllStringSubmatch(text, -1) {
+		if m[1] != "" {
+			hours = append(hours, HoursRule{Days: parseWeekdays(m[1])})
+			cur = len(hours) - 1
+			continue
+		}
+		if cur < 0 {
+			hours = append(hours, HoursRule{Days: [7]bool{true, true, true, true, true, true, true}})
+			cur = 0
+		}
+		start := clock(m[2], m[3], m[4])
+		end := clock(m[5], m[6], m[7])
+		if end <= start {
+			end += 24 * 60
+		}
+		hours[cur].Intervals = append(hours[cur].Intervals, Interval{Start: start, End: end})
+	}
+	hours = slices.DeleteFunc(hours, func(r HoursRule) bool { return len(r.Intervals) == 0 })
+	if len(hours) == 0 {
+		return nil
+	}
+	return hours
+}
+
+func clock(nextDay, h, m string) int {
+	hh, _ := strconv.Atoi(h)
+	mm, _ := strconv.Atoi(m)
+	t := hh*60 + mm
+	if nextDay != "" {
+		t += 24 * 60
+	}
+	return t
+}
+
+// parseWeekdays reads weekday headers such as "月～土、祝前日", "土日" or
+// "日、祝日". Holiday terms (祝, 祝日, 祝前日) are dropped.
+func parseWeekdays(s string) [7]bool {
+	var days [7]bool
+	s = strings.NewReplacer("祝前日", "", "祝日", "", "祝前", "", "祝", "").Replace(s)
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '、' || r == '・' || r == ' ' }) {
+		runes := []rune(strings.NewReplacer("~", "～", "〜", "～").Replace(part))
+		if i := slices.Index(runes, '～'); i > 0 && i < len(runes)-1 {
+			from, to := slices.Index(weekdayChars, runes[i-1]), slices.Index(weekdayChars, runes[i+1])
+			if from >= 0 && to >= 0 {
+				for d := from; ; d = (d + 1) % 7 {
+					days[d] = true
+					if d == to {
+						break
+					}
+				}
+			}
+			continue
+		}
+		for _, r := range runes {
+			if d := slices.Index(weekdayChars, r); d >= 0 {
+				days[d] = true
+			}
+		}
+	}
+	return days
+}
+
+// On returns the opening periods on a weekday, or nil when the shop is
+// closed that day.
+func (h Hours) On(day time.Weekday) []Interval {
+	var out []Interval
+	for _, r := range h {
+		if r.Days[day] {
+			out = append(out, r.Intervals...)
+		}
+	}
+	return out
+}
+
+// OpenUntil returns when the shop closes if it is open at minute at of
+// day (minutes after midnight, past 1440 for after midnight), or false.
+func (h Hours) OpenUntil(day time.Weekday, at int) (int, bool) {
+	for _, iv := range h.On(day) {
+		if iv.Start <= at && at < iv.End {
+			return iv.End, true
+		}
+	}
+	return 0, false
+}
+
+// Closing returns the latest closing time on a weekday, or false when the
+// shop is closed that day.
+func (h Hours) Closing(day time.Weekday) (int, bool) {
+	ivs := h.On(day)
+	if len(ivs) == 0 {
+		return 0, false
+	}
+	return slices.MaxFunc(ivs, func(a, b Interval) int { return a.End - b.End }).End, true
+}
+
+// FormatClock renders minutes after midnight as "22:30", or "翌2:00" past
+// midnight.
+func FormatClock(t int) string {
+	if t >= 24*60 {
+		t -= 24 * 60
+		return fmt.Sprintf("翌%d:%02d", t/60, t%60)
+	}
+	return fmt.Sprintf("%d:%02d", t/60, t%60)
+}
+
+// ParseClock parses "22:30", "25:00" or "翌1:00" into minutes after
+// midnight.
+func ParseClock(s string) (int, error) {
+	s = widthFolder.Replace(strings.TrimSpace(s))
+	rest, next := strings.CutPrefix(s, "翌")
+	h, m, ok := strings.Cut(rest, ":")
+	hh, err1 := strconv.Atoi(h)
+	mm, err2 := strconv.Atoi(m)
+	if !ok || err1 != nil || err2 != nil || hh < 0 || mm < 0 || mm > 59 || hh > 47 {
+		return 0, fmt.Errorf("invalid time %q (want e.g. 22:30 or 翌1:00)", s)
+	}
+	t := hh*60 + mm
+	if next {
+		t += 24 * 60
+	}
+	return t, nil
+}
+
+// Hours parses the shop's opening hours from Open.
+func (s *Shop) Hours() Hours {
+	return ParseHours(s.Open)
+}
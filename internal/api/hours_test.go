@@ -0,0 +1,79 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHours(t *testing.T) {
+	h := ParseHours("月～土、祝前日: 17:00～翌2:00 （料理L.O. 翌1:00 ドリンクL.O. 翌1:30）日、祝日: 17:00～23:00 （料理L.O. 22:00）")
+	if len(h) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", h)
+	}
+	if got := h.On(time.Friday); len(got) != 1 || got[0] != (Interval{17 * 60, 26 * 60}) {
+		t.Fatalf("Friday: got %+v", got)
+	}
+	if got := h.On(time.Sunday); len(got) != 1 || got[0].End != 23*60 {
+		t.Fatalf("Sunday: got %+v", got)
+	}
+
+	h = ParseHours("11:30～14:00、17:00～22:00")
+	if got := h.On(time.Wednesday); len(got) != 2 || got[1] != (Interval{17 * 60, 22 * 60}) {
+		t.Fatalf("expected lunch and dinner every day, got %+v", got)
+	}
+
+	h = ParseHours("火～日: 18:00～5:00")
+	if h.On(time.Monday) != nil || h.On(time.Sunday)[0].End != 29*60 {
+		t.Fatalf("expected closed Mondays and open past midnight, got %+v", h)
+	}
+
+	h = ParseHours("土日: 12:00～24:00")
+	if h.On(time.Saturday) == nil || h.On(time.Sunday) == nil || h.On(time.Friday) != nil {
+		t.Fatalf("expected weekends only, got %+v", h)
+	}
+
+	if h := ParseHours("お問い合わせください"); h != nil {
+		t.Fatalf("expected nil for text without times, got %+v", h)
+	}
+}
+
+func TestHoursOpenUntil(t *testing.T) {
+	h := ParseHours("月～土: 11:30～14:00、17:00～翌1:00")
+	if until, ok := h.OpenUntil(time.Monday, 22*60); !ok || until != 25*60 {
+		t.Fatalf("OpenUntil = %d, %v", until, ok)
+	}
+	if _, ok := h.OpenUntil(time.Monday, 15*60); ok {
+		t.Fatal("expected closed between lunch and dinner")
+	}
+	if _, ok := h.OpenUntil(time.Sunday, 22*60); ok {
+		t.Fatal("expected closed on Sunday")
+	}
+	if c, ok := h.Closing(time.Tuesday); !ok || c != 25*60 {
+		t.Fatalf("Closing = %d, %v", c, ok)
+	}
+}
+
+func TestClock(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want int
+	}{
+		{"22:30", 22*60 + 30},
+		{"翌1:00", 25 * 60},
+		{"25:00", 25 * 60},
+	} {
+		got, err := ParseClock(tt.in)
+		if err != nil || got != tt.want {
+			t.Fatalf("ParseClock(%q) = %d, %v", tt.in, got, err)
+		}
+	}
+	if _, err := ParseClock("10pm"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := FormatClock(26 * 60); got != "翌2:00" {
+		t.Fatalf("FormatClock = %s", got)
+	}
+	if got := FormatClock(21*60 + 5); got != "21:05" {
+		t.Fatalf("FormatClock = %s", got)
+	}
+}
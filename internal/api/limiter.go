@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket: it allows bursts of up to burst requests
+// and refills at a steady rate. It is safe for concurrent use, so one
+// limiter can pace every request of a process. A nil *RateLimiter does not
+// limit.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allows perSecond requests per second on average and up to
+// burst at once (at least 1). perSecond of 0 or less is unlimited.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	b := float64(max(burst, 1))
+	return &RateLimiter{rate: perSecond, burst: b, tokens: b, last: time.Now()}
+}
+
+// Wait blocks until the caller may make a request or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// Take the token now, even if it is owed; later callers queue behind.
+	l.tokens--
+	d := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.tokens++ // give the token back
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+	return nil
+}
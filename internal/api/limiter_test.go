@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(100, 1)
+	start := time.Now()
+	for range 5 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Fatalf("expected requests to be spaced 10ms apart, took %v", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = NewRateLimiter(1, 1)
+	_ = l.Wait(ctx) // first request is immediate
+	if err := l.Wait(ctx); err == nil {
+		t.Fatalf("expected cancelled wait to fail")
+	}
+
+	var unlimited *RateLimiter
+	if NewRateLimiter(0, 1) != nil || unlimited.Wait(ctx) != nil {
+		t.Fatalf("expected a nil limiter not to limit")
+	}
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l := NewRateLimiter(20, 5)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range 5 {
+		wg.Go(func() { _ = l.Wait(context.Background()) })
+	}
+	wg.Wait()
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Fatalf("expected a burst of 5 to pass at once, took %v", d)
+	}
+
+	start = time.Now()
+	for range 2 {
+		wg.Go(func() { _ = l.Wait(context.Background()) })
+	}
+	wg.Wait()
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Fatalf("expected requests past the burst to wait for refills, took %v", d)
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+// MaxIDsPerRequest is the most shop IDs /gourmet/v1/ accepts at once.
+const MaxIDsPerRequest = 20
+
+// ShopsByID looks up shops by ID, MaxIDsPerRequest at a time. Shops are
+// returned in the order of ids (duplicates collapsed), with access text
+// parsed; IDs the API no longer knows are returned as missing.
+func (c *Client) ShopsByID(ids []string) (shops []Shop, missing []string, err error) {
+	var unique []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found := make(map[string]Shop, len(unique))
+	for start := 0; start < len(unique); start += MaxIDsPerRequest {
+		chunk := unique[start:min(start+MaxIDsPerRequest, len(unique))]
+		count := len(chunk)
+		var resp GourmetResponse
+		if err := c.Get("/gourmet/v1/", GourmetSearchParams{ID: chunk, Count: &count}, &resp); err != nil {
+			return nil, nil, err
+		}
+		for _, s := range resp.Results.Shops {
+			s.ParseAccess()
+			found[s.ID] = s
+		}
+	}
+
+	for _, id := range unique {
+		if s, ok := found[id]; ok {
+			shops = append(shops, s)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return shops, missing, nil
+}
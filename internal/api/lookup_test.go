@@ -0,0 +1,48 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestShopsByID(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query()["id"]
+		requests = append(requests, r.URL.Query().Get("count"))
+		var shops []string
+		// Answer in reverse order and drop retired IDs.
+		for i := len(ids) - 1; i >= 0; i-- {
+			if !strings.HasPrefix(ids[i], "gone") {
+				shops = append(shops, fmt.Sprintf(`{"id":%q}`, ids[i]))
+			}
+		}
+		_, _ = fmt.Fprintf(w, `{"results":{"results_available":%d,"shop":[%s]}}`, len(shops), strings.Join(shops, ","))
+	}))
+	defer srv.Close()
+	c := NewClient("k")
+	c.BaseURL = srv.URL
+
+	var ids []string
+	for i := range 45 {
+		ids = append(ids, fmt.Sprintf("J%03d", i))
+	}
+	ids = append(ids, "gone1", "J000", "gone2")
+
+	shops, missing, err := c.ShopsByID(ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 3 || requests[0] != "20" || requests[2] != "7" {
+		t.Fatalf("expected 3 requests of up to 20, got counts %v", requests)
+	}
+	if len(shops) != 45 || shops[0].ID != "J000" || shops[44].ID != "J044" {
+		t.Fatalf("expected 45 shops in input order, got %d", len(shops))
+	}
+	if strings.Join(missing, ",") != "gone1,gone2" {
+		t.Fatalf("unexpected missing %v", missing)
+	}
+}
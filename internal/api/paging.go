@@ -0,0 +1,34 @@
+package api
+
+// MaxGourmetCount is the largest page size /gourmet/v1/ accepts.
+const MaxGourmetCount = 100
+
+// GourmetPages fetches successive pages of up to MaxGourmetCount shops,
+// beginning at p.Start, and calls fn with each. It stops when fn returns
+// false, after maxPages pages (0 means no limit), or when results run out.
+// p.Count is ignored.
+func (c *Client) GourmetPages(p GourmetSearchParams, maxPages int, fn func(r *GourmetResults) bool) error {
+	start := 1
+	if p.Start != nil {
+		start = *p.Start
+	}
+	count := MaxGourmetCount
+	p.Count = &count
+	for page := 0; maxPages <= 0 || page < maxPages; page++ {
+		pageStart := start
+		p.Start = &pageStart
+		var resp GourmetResponse
+		if err := c.Get("/gourmet/v1/", p, &resp); err != nil {
+			return err
+		}
+		if !fn(&resp.Results) {
+			return nil
+		}
+		n := len(resp.Results.Shops)
+		start += n
+		if n == 0 || start > resp.Results.ResultsAvailable {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/apitest"
+)
+
+func TestGourmetPages(t *testing.T) {
+	fake := &apitest.Paged{Total: 250}
+	c := NewClient("k")
+	c.BaseURL = fake.Start(t)
+
+	var ids []string
+	err := c.GourmetPages(GourmetSearchParams{}, 0, func(r *GourmetResults) bool {
+		for _, s := range r.Shops {
+			ids = append(ids, s.ID)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 250 || ids[249] != "J250" {
+		t.Fatalf("expected all 250 shops, got %d", len(ids))
+	}
+	want := []string{"1/100", "101/100", "201/100"}
+	if got := fake.Requests(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected requests %v, got %v", want, got)
+	}
+}
+
+func TestGourmetPages_Limits(t *testing.T) {
+	fake := &apitest.Paged{Total: 1000}
+	c := NewClient("k")
+	c.BaseURL = fake.Start(t)
+
+	start := 51
+	err := c.GourmetPages(GourmetSearchParams{Start: &start}, 2, func(r *GourmetResults) bool { return true })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fake.Requests(); fmt.Sprint(got) != "[51/100 151/100]" {
+		t.Fatalf("expected two pages from 51, got %v", got)
+	}
+
+	fake.Reset()
+	err = c.GourmetPages(GourmetSearchParams{}, 0, func(r *GourmetResults) bool { return false })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fake.Requests(); len(got) != 1 {
+		t.Fatalf("expected fn returning false to stop paging, got %v", got)
+	}
+}
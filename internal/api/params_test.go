@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-querystring/query"
@@ -62,3 +63,20 @@ func TestShopSearchParams_Encode(t *testing.T) {
 
 func strPtr(s string) *string { return &s }
 func intPtr(i int) *int       { return &i }
+
+func TestGourmetSearchParams_JSONMatchesQueryNames(t *testing.T) {
+	var p GourmetSearchParams
+	data := `{"keyword":"ramen","free_drink":true,"middle_area":["Y005"],"party_capacity":20}`
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	vals, err := query.Values(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for key, want := range map[string]string{"keyword": "ramen", "free_drink": "1", "middle_area": "Y005", "party_capacity": "20"} {
+		if vals.Get(key) != want {
+			t.Fatalf("expected %s=%s, got %q", key, want, vals.Get(key))
+		}
+	}
+}
@@ -70,6 +70,9 @@ type Shop struct {
 	ShopDetailMemo string     `json:"shop_detail_memo"`
 	CouponURLs     CouponURLs `json:"coupon_urls"`
 
+	// Derived client-side by ParseAccess; not part of the API response.
+	AccessRoutes []AccessRoute `json:"access_routes,omitempty"`
+
 	// Area hierarchy
 	LargeServiceArea CodeName `json:"large_service_area"`
 	ServiceArea      CodeName `json:"service_area"`
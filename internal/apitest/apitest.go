@@ -0,0 +1,93 @@
+// Package apitest serves a fake HotPepper API for tests.
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Paged is a fake API serving generated items for every endpoint, paged by
+// start and count like the real one.
+type Paged struct {
+	// Total is the number of items each table has, unless Sizes sets it.
+	Total int
+	Sizes map[string]int
+	// Item renders item i (from 1) of a table, e.g. "gourmet" or
+	// "middle_area", as a JSON object. Shops default to {"id":"J001"} and
+	// master rows to {"code":"A001","name":"item 1"}.
+	Item func(table string, i int) string
+	// MaxCount caps the items per page, like the API; 0 for no cap.
+	MaxCount int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// Start serves p until the test ends and returns its base URL.
+func (p *Paged) Start(t testing.TB) string {
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// Requests returns the "start/count" of each request so far.
+func (p *Paged) Requests() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.requests...)
+}
+
+// Reset forgets the requests so far.
+func (p *Paged) Reset() {
+	p.mu.Lock()
+	p.requests = nil
+	p.mu.Unlock()
+}
+
+func (p *Paged) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p.mu.Lock()
+	p.requests = append(p.requests, q.Get("start")+"/"+q.Get("count"))
+	p.mu.Unlock()
+
+	table := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/"), "/v1/")
+	key := table
+	if table == "gourmet" {
+		key = "shop"
+	}
+	total := p.Total
+	if n, ok := p.Sizes[table]; ok {
+		total = n
+	}
+	start, _ := strconv.Atoi(q.Get("start"))
+	start = max(start, 1)
+	count, err := strconv.Atoi(q.Get("count"))
+	if err != nil {
+		count = total
+	}
+	if p.MaxCount > 0 {
+		count = min(count, p.MaxCount)
+	}
+	var items []string
+	for i := start; i < start+count && i <= total; i++ {
+		items = append(items, p.item(table, i))
+	}
+	_, _ = fmt.Fprintf(w, `{"results":{"api_version":"1.30","results_available":%d,"results_returned":"%d","results_start":%d,%q:[%s]}}`,
+		total, len(items), start, key, strings.Join(items, ","))
+}
+
+func (p *Paged) item(table string, i int) string {
+	switch {
+	case p.Item != nil:
+		return p.Item(table, i)
+	case table == "gourmet":
+		return fmt.Sprintf(`{"id":"J%03d"}`, i)
+	default:
+		return fmt.Sprintf(`{"code":"A%03d","name":"item %d"}`, i, i)
+	}
+}
@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Query is one line of a batch file: gourmet search parameters with an
+// optional label. Err is set when the line could not be parsed.
+type Query struct {
+	Line   int
+	Label  string
+	Params api.GourmetSearchParams
+	Err    error
+}
+
+// Result is the outcome of one query. Exactly one of Results and Error is
+// set.
+type Result struct {
+	Line    int                 `json:"line"`
+	Label   string              `json:"label,omitempty"`
+	Results *api.GourmetResults `json:"results,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+// line is the JSON shape of a batch file line.
+type line struct {
+	Label string `json:"label"`
+	api.GourmetSearchParams
+}
+
+// Read parses a batch file of one JSON object per line. Blank lines and
+// lines starting with # are skipped. Lines that fail to parse, including
+// ones with unknown fields, are returned with Err set rather than aborting.
+func Read(r io.Reader) ([]Query, error) {
+	var queries []Query
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		text := bytes.TrimSpace(sc.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		var l line
+		dec := json.NewDecoder(bytes.NewReader(text))
+		dec.DisallowUnknownFields()
+		q := Query{Line: n}
+		if err := dec.Decode(&l); err != nil {
+			q.Err = fmt.Errorf("line %d: %w", n, err)
+			_ = json.Unmarshal(text, &l) // keep the label when only a field is wrong
+			q.Label = l.Label
+		} else {
+			q.Label, q.Params = l.Label, l.GourmetSearchParams
+		}
+		queries = append(queries, q)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// Run executes queries against c with at most concurrency in flight and
+// calls emit with each result in input order, as soon as it and every
+// earlier result are done. A failed query does not stop the others.
+func Run(c *api.Client, queries []Query, concurrency int, emit func(Result) error) error {
+	concurrency = max(concurrency, 1)
+	results := make([]Result, len(queries))
+	done := make([]chan struct{}, len(queries))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Go(func() {
+			defer close(done[i])
+			results[i] = Result{Line: q.Line, Label: q.Label}
+			if q.Err != nil {
+				results[i].Error = q.Err.Error()
+				return
+			}
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var resp api.GourmetResponse
+			if err := c.Get("/gourmet/v1/", q.Params, &resp); err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			for j := range resp.Results.Shops {
+				resp.Results.Shops[j].ParseAccess()
+			}
+			results[i].Results = &resp.Results
+		})
+	}
+
+	var err error
+	for i := range queries {
+		<-done[i]
+		if err == nil {
+			err = emit(results[i])
+		}
+		results[i] = Result{} // emitted; release the shops
+	}
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,99 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestRead(t *testing.T) {
+	input := `{"label":"shimbashi izakaya","genre":["G001"],"middle_area":["Y005"],"count":5}
+
+# comment
+{"keyword":"ramen","wifi":true}
+{"label":"typo","keywrd":"ramen"}
+not json
+`
+	queries, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 4 {
+		t.Fatalf("expected 4 queries, got %d", len(queries))
+	}
+	q := queries[0]
+	if q.Line != 1 || q.Label != "shimbashi izakaya" || q.Params.Genre[0] != "G001" || *q.Params.Count != 5 || q.Err != nil {
+		t.Fatalf("unexpected first query %+v", q)
+	}
+	if q := queries[1]; q.Line != 4 || *q.Params.Keyword != "ramen" || !q.Params.WiFi {
+		t.Fatalf("unexpected second query %+v", q)
+	}
+	if q := queries[2]; q.Err == nil || !strings.Contains(q.Err.Error(), "line 5") || q.Label != "typo" {
+		t.Fatalf("expected labeled unknown field error on line 5, got %+v", q)
+	}
+	if queries[3].Err == nil {
+		t.Fatalf("expected parse error for invalid JSON")
+	}
+}
+
+func TestRun(t *testing.T) {
+	keywords := []string{"a", "b", "fail", "c", "d", "e"}
+	delays := map[string]time.Duration{}
+	for i, kw := range keywords {
+		delays[kw] = time.Duration(30-5*i) * time.Millisecond
+	}
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		keyword := r.URL.Query().Get("keyword")
+		// Later queries answer sooner, to check output stays in input order.
+		time.Sleep(delays[keyword])
+		if keyword == "fail" {
+			_, _ = fmt.Fprint(w, `{"results":{"error":[{"code":3000,"message":"bad query"}]}}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"results":{"results_available":1,"results_returned":"1","shop":[{"id":%q}]}}`, keyword)
+	}))
+	defer srv.Close()
+	c := api.NewClient("k")
+	c.BaseURL = srv.URL
+
+	var queries []Query
+	for i, kw := range keywords {
+		queries = append(queries, Query{Line: i + 1, Label: kw, Params: api.GourmetSearchParams{Keyword: &kw}})
+	}
+	queries = append(queries, Query{Line: 7, Err: errors.New("line 7: bad json")})
+
+	var got []Result
+	err := Run(c, queries, 2, func(r Result) error {
+		got = append(got, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(queries) {
+		t.Fatalf("expected %d results, got %d", len(queries), len(got))
+	}
+	for i, r := range got {
+		if r.Line != i+1 {
+			t.Fatalf("expected results in input order, got line %d at %d", r.Line, i)
+		}
+	}
+	if got[0].Results.Shops[0].ID != "a" || got[2].Error == "" || got[2].Results != nil || got[6].Error != "line 7: bad json" {
+		t.Fatalf("unexpected results %+v", got)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, saw %d", p)
+	}
+}
@@ -0,0 +1,79 @@
+package compare
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Row is one field of a comparison: its value for each shop, and whether
+// the shops differ in it.
+type Row struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"`
+	Differ bool     `json:"differ"`
+}
+
+// field reads a value from a shop. Key, when set, is what is compared
+// instead of the displayed value.
+type field struct {
+	name  string
+	value func(s *api.Shop) string
+	key   func(s *api.Shop) string
+}
+
+var fields = []field{
+	{name: "genre", value: func(s *api.Shop) string { return s.Genre.Name }},
+	{name: "sub_genre", value: func(s *api.Shop) string { return s.SubGenre.Name }},
+	{name: "budget", value: func(s *api.Shop) string { return s.Budget.Name }},
+	{name: "budget_average", value: func(s *api.Shop) string { return s.Budget.Average }},
+	{name: "capacity", value: func(s *api.Shop) string { return count(s.Capacity) }},
+	{name: "party_capacity", value: func(s *api.Shop) string { return count(s.PartyCapacity) }},
+	{name: "open", value: func(s *api.Shop) string { return s.Open }},
+	{name: "close", value: func(s *api.Shop) string { return s.Close }},
+	{name: "station", value: func(s *api.Shop) string { return s.StationName }},
+	{name: "access", value: func(s *api.Shop) string { return s.Access }},
+	{name: "middle_area", value: func(s *api.Shop) string { return s.MiddleArea.Name }},
+	{name: "address", value: func(s *api.Shop) string { return s.Address }},
+}
+
+func init() {
+	for _, a := range api.Amenities {
+		get := a.Shop
+		fields = append(fields, field{
+			name:  a.Name,
+			value: get,
+			// Amenities differ in availability, not wording:
+			// "あり" and "あり ：完全個室" are the same.
+			key: func(s *api.Shop) string { return strconv.FormatBool(api.HasAmenity(get(s))) },
+		})
+	}
+}
+
+// Shops compares the shops field by field: budget, capacities, hours,
+// access, area and every amenity.
+func Shops(shops []api.Shop) []Row {
+	rows := make([]Row, 0, len(fields))
+	for _, f := range fields {
+		r := Row{Field: f.name, Values: make([]string, len(shops))}
+		keys := make([]string, len(shops))
+		for i := range shops {
+			r.Values[i] = f.value(&shops[i])
+			keys[i] = r.Values[i]
+			if f.key != nil {
+				keys[i] = f.key(&shops[i])
+			}
+		}
+		r.Differ = len(slices.Compact(keys)) > 1
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func count(n api.FlexInt) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(n))
+}
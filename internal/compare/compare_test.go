@@ -0,0 +1,47 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestShops(t *testing.T) {
+	shops := []api.Shop{
+		{Genre: api.CodeName{Name: "居酒屋"}, Capacity: 40, PrivateRoom: "あり ：完全個室", Karaoke: "あり", Open: "17:00～23:00"},
+		{Genre: api.CodeName{Name: "居酒屋"}, Capacity: 20, PrivateRoom: "あり", Karaoke: "なし", Open: "17:00～23:00"},
+		{Genre: api.CodeName{Name: "居酒屋"}, PrivateRoom: "あり", Open: "17:00～23:00"},
+	}
+	rows := Shops(shops)
+	byField := map[string]Row{}
+	for _, r := range rows {
+		if len(r.Values) != 3 {
+			t.Fatalf("%s: expected a value per shop, got %v", r.Field, r.Values)
+		}
+		byField[r.Field] = r
+	}
+
+	tests := []struct {
+		field  string
+		differ bool
+	}{
+		{"genre", false},
+		{"open", false},
+		{"capacity", true},
+		{"private_room", false}, // same availability, different wording
+		{"karaoke", true},
+		{"wifi", false},
+	}
+	for _, tt := range tests {
+		r, ok := byField[tt.field]
+		if !ok {
+			t.Fatalf("missing field %s", tt.field)
+		}
+		if r.Differ != tt.differ {
+			t.Fatalf("%s: differ = %v, want %v (%v)", tt.field, r.Differ, tt.differ, r.Values)
+		}
+	}
+	if got := byField["capacity"].Values; got[0] != "40" || got[2] != "" {
+		t.Fatalf("unexpected capacities %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jackchuka/hpp/internal/quota"
+	"github.com/jackchuka/hpp/internal/rank"
+)
+
+// Config holds user settings, stored as config.json in Dir.
+type Config struct {
+	RateLimit RateLimit  `json:"rate_limit"`
+	Quota     quota.Caps `json:"quota"`
+	// Rank holds the --rank preference profiles by name.
+	Rank map[string]rank.Profile `json:"rank,omitempty"`
+}
+
+// RateLimit paces the requests of each hpp process.
+type RateLimit struct {
+	PerSecond float64 `json:"per_second"` // 0 for unlimited
+	Burst     int     `json:"burst"`
+}
+
+// Default returns the settings used when nothing is configured.
+func Default() *Config {
+	return &Config{
+		RateLimit: RateLimit{PerSecond: 5, Burst: 5},
+		Quota:     quota.Caps{Action: quota.ActionWarn},
+	}
+}
+
+// Dir returns hpp's directory in the user's config directory
+// (e.g. ~/.config/hpp).
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating config directory: %w", err)
+	}
+	return filepath.Join(dir, "hpp"), nil
+}
+
+// Path returns the path of config.json in Dir.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// Load reads the config at path. Settings missing from the file, or the
+// whole file, take their Default values.
+func Load(path string) (*Config, error) {
+	c := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+	if err := c.Quota.Validate(); err != nil {
+		return nil, fmt.Errorf("config %s: quota: %w", path, err)
+	}
+	for name, p := range c.Rank {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("config %s: rank profile %s: %w", path, name, err)
+		}
+	}
+	return c, nil
+}
+
+// RankProfile returns the --rank profile with the given name. A profile
+// named "default" falls back to rank.Default when not configured.
+func (c *Config) RankProfile(name string) (*rank.Profile, error) {
+	if p, ok := c.Rank[name]; ok {
+		return &p, nil
+	}
+	if name == "default" {
+		p := rank.Default
+		return &p, nil
+	}
+	return nil, fmt.Errorf("no rank profile %q in config", name)
+}
+
+// Save writes c to path, creating its directory.
+func Save(path string, c *Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/quota"
+	"github.com/jackchuka/hpp/internal/rank"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Fatalf("expected defaults for a missing file, got %+v", c)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"quota": {"daily": 100}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err = Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Quota.Daily != 100 || c.Quota.Action != quota.ActionWarn || c.RateLimit.PerSecond != 5 {
+		t.Fatalf("expected missing settings to keep defaults, got %+v", c)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, data := range []string{`{"quota": {"action": "block"}}`, `{"qouta": {}}`, `{"rank": {"lunch": {"budget": 1}}}`} {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hpp", "config.json")
+	c := Default()
+	c.RateLimit.PerSecond = 0
+	c.Quota = quota.Caps{Daily: 50, Monthly: 1000, Action: quota.ActionRefuse}
+	c.Rank = map[string]rank.Profile{"lunch": {Distance: 2, Budget: 1, BudgetYen: 1200, Amenities: map[string]float64{"non_smoking": 1}}}
+	if err := Save(path, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Fatalf("expected %+v, got %+v", c, got)
+	}
+}
+
+func TestRankProfile(t *testing.T) {
+	c := Default()
+	if p, err := c.RankProfile("default"); err != nil || p.Distance != rank.Default.Distance {
+		t.Fatalf("expected the built-in default profile, got %+v, %v", p, err)
+	}
+	if _, err := c.RankProfile("lunch"); err == nil {
+		t.Fatal("expected an error for a missing profile")
+	}
+	c.Rank = map[string]rank.Profile{"default": {Budget: 1, BudgetYen: 3000}}
+	if p, err := c.RankProfile("default"); err != nil || p.Budget != 1 || p.Distance != 0 {
+		t.Fatalf("expected the configured default profile, got %+v, %v", p, err)
+	}
+}
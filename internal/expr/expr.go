@@ -0,0 +1,525 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the static type of a field or sub-expression.
+type Kind int
+
+const (
+	Bool Kind = iota
+	Number
+	String
+	// Flag is free text that also has a truth value, such as an amenity
+	// ("あり", "全面禁煙", "なし"). It can be used as a condition on its own or
+	// compared as a string.
+	Flag
+)
+
+func (k Kind) String() string {
+	return [...]string{"boolean", "number", "string", "flag"}[k]
+}
+
+// Value is a field value supplied at evaluation time.
+type Value struct {
+	Bool bool
+	Num  float64
+	Str  string
+}
+
+// Env looks up field values by name. It is only asked for names declared
+// when the expression was compiled.
+type Env func(name string) Value
+
+// Error is a compile error pointing at the offending part of the source.
+type Error struct {
+	Src      string
+	Pos, End int // byte offsets into Src
+	Msg      string
+}
+
+func (e *Error) Error() string {
+	end := max(e.End, e.Pos+1)
+	return fmt.Sprintf("%s at column %d\n  %s\n  %s%s",
+		e.Msg, width(e.Src[:e.Pos])+1, e.Src,
+		strings.Repeat(" ", width(e.Src[:e.Pos])),
+		strings.Repeat("^", max(1, width(e.Src[e.Pos:min(end, len(e.Src))]))))
+}
+
+// width approximates the terminal width of s, counting CJK as two columns.
+func width(s string) int {
+	w := 0
+	for _, r := range s {
+		if r >= 0x2E80 && !(r >= 0xFF61 && r <= 0xFF9F) {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
+
+// Expr is a compiled boolean expression.
+type Expr struct {
+	root node
+}
+
+// Compile parses src against the declared fields and checks that every
+// operator is applied to operands of a suitable type.
+//
+// Grammar (keywords are case-insensitive):
+//
+//	expr  := and { ("or" | "||") and }
+//	and   := unary { ("and" | "&&") unary }
+//	unary := ("not" | "!") unary | cmp
+//	cmp   := operand [ ("==" | "!=" | "<" | "<=" | ">" | ">=") operand
+//	                 | "contains" operand
+//	                 | ("=~" | "!~" | "matches") string
+//	                 | ["not"] "in" "[" literal { "," literal } "]" ]
+//	operand := field | number | string | "true" | "false" | "(" expr ")"
+func Compile(src string, fields map[string]Kind) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks, fields: fields}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	if !isCondition(n.kind()) {
+		return nil, p.errorAt(n.span(), "expected a condition, got a %s", n.kind())
+	}
+	return &Expr{root: n}, nil
+}
+
+// Eval evaluates the expression with field values from env.
+func (e *Expr) Eval(env Env) bool {
+	return e.root.eval(env).Bool
+}
+
+func isCondition(k Kind) bool { return k == Bool || k == Flag }
+func isText(k Kind) bool      { return k == String || k == Flag }
+
+type span struct{ pos, end int }
+
+type node interface {
+	kind() Kind
+	span() span
+	eval(env Env) Value
+}
+
+type parser struct {
+	src    string
+	toks   []token
+	i      int
+	fields map[string]Kind
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+func (p *parser) next() token { t := p.toks[p.i]; p.i++; return t }
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &Error{Src: p.src, Pos: t.pos, End: t.end, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) errorAt(s span, format string, args ...any) error {
+	return &Error{Src: p.src, Pos: s.pos, End: s.end, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.keyword("or") || t.kind == tokOp && t.text == "||"; t = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(t, "or", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.keyword("and") || t.kind == tokOp && t.text == "&&"; t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(t, "and", left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) logical(op token, name string, left, right node) (node, error) {
+	for _, n := range []node{left, right} {
+		if !isCondition(n.kind()) {
+			return nil, p.errorAt(n.span(), "%q needs conditions on both sides, got a %s", op.text, n.kind())
+		}
+	}
+	return &logicalNode{or: name == "or", left: left, right: right}, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if t.keyword("not") || t.kind == tokOp && t.text == "!" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if !isCondition(operand.kind()) {
+			return nil, p.errorAt(operand.span(), "%q needs a condition, got a %s", t.text, operand.kind())
+		}
+		return &notNode{pos: t.pos, operand: operand}, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *parser) parseCmp() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && comparisons[t.text]:
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return p.compare(t, left, right)
+	case t.keyword("contains"):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if !isText(left.kind()) || !isText(right.kind()) {
+			return nil, p.errorf(t, "contains needs strings on both sides, got %s and %s", left.kind(), right.kind())
+		}
+		return &containsNode{left: left, right: right}, nil
+	case t.kind == tokOp && (t.text == "=~" || t.text == "!~") || t.keyword("matches"):
+		p.next()
+		return p.parseMatch(t, left)
+	case t.keyword("in"):
+		p.next()
+		return p.parseIn(t, left, false)
+	case t.keyword("not") && p.toks[p.i+1].keyword("in"):
+		p.next()
+		p.next()
+		return p.parseIn(t, left, true)
+	}
+	return left, nil
+}
+
+var comparisons = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *parser) compare(op token, left, right node) (node, error) {
+	lk, rk := left.kind(), right.kind()
+	switch op.text {
+	case "==", "!=":
+		if lk != rk && !(isText(lk) && isText(rk)) {
+			return nil, p.errorf(op, "cannot compare %s with %s", lk, rk)
+		}
+	default:
+		if lk != Number || rk != Number {
+			return nil, p.errorf(op, "%s needs numbers on both sides, got %s and %s", op.text, lk, rk)
+		}
+	}
+	if lk == Flag {
+		lk = String
+	}
+	return &compareNode{op: op.text, operands: lk, left: left, right: right}, nil
+}
+
+func (p *parser) parseMatch(op token, left node) (node, error) {
+	if !isText(left.kind()) {
+		return nil, p.errorf(op, "%s needs a string on the left, got a %s", op.text, left.kind())
+	}
+	t := p.next()
+	if t.kind != tokString {
+		return nil, p.errorf(t, "expected a quoted regular expression, got %s", t)
+	}
+	re, err := regexp.Compile(t.text)
+	if err != nil {
+		return nil, p.errorf(t, "invalid regular expression: %v", err)
+	}
+	return &matchNode{negate: op.text == "!~", left: left, re: re, end: t.end}, nil
+}
+
+func (p *parser) parseIn(op token, left node, negate bool) (node, error) {
+	lk := left.kind()
+	if lk == Flag {
+		lk = String
+	}
+	if lk != String && lk != Number {
+		return nil, p.errorf(op, "in needs a string or number on the left, got a %s", left.kind())
+	}
+	if t := p.next(); t.kind != tokLBrack {
+		return nil, p.errorf(t, "expected [ after in, got %s", t)
+	}
+	n := &inNode{negate: negate, left: left}
+	for {
+		t := p.next()
+		if t.kind == tokRBrack && len(n.strs)+len(n.nums) == 0 {
+			n.end = t.end
+			return n, nil
+		}
+		switch {
+		case t.kind == tokString && lk == String:
+			n.strs = append(n.strs, t.text)
+		case t.kind == tokNumber && lk == Number:
+			v, err := parseNumber(t.text)
+			if err != nil {
+				return nil, p.errorf(t, "invalid number %s", t)
+			}
+			n.nums = append(n.nums, v)
+		default:
+			return nil, p.errorf(t, "expected a %s in list, got %s", lk, t)
+		}
+		switch t := p.next(); t.kind {
+		case tokComma:
+		case tokRBrack:
+			n.end = t.end
+			return n, nil
+		default:
+			return nil, p.errorf(t, "expected , or ] in list, got %s", t)
+		}
+	}
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing.kind != tokRParen {
+			return nil, p.errorf(closing, "expected ), got %s", closing)
+		}
+		return &parenNode{node: n, s: span{t.pos, closing.end}}, nil
+	case t.kind == tokNumber:
+		v, err := parseNumber(t.text)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %s", t)
+		}
+		return &literal{k: Number, v: Value{Num: v}, s: span{t.pos, t.end}}, nil
+	case t.kind == tokString:
+		return &literal{k: String, v: Value{Str: t.text}, s: span{t.pos, t.end}}, nil
+	case t.keyword("true"), t.keyword("false"):
+		return &literal{k: Bool, v: Value{Bool: t.keyword("true")}, s: span{t.pos, t.end}}, nil
+	case t.kind == tokIdent:
+		for _, kw := range []string{"and", "or", "not", "in", "contains", "matches"} {
+			if t.keyword(kw) {
+				return nil, p.errorf(t, "unexpected %s", t)
+			}
+		}
+		k, ok := p.fields[t.text]
+		if !ok {
+			return nil, p.errorf(t, "unknown field %s%s", t, p.suggest(t.text))
+		}
+		return &fieldNode{name: t.text, k: k, s: span{t.pos, t.end}}, nil
+	}
+	return nil, p.errorf(t, "expected a field or value, got %s", t)
+}
+
+// suggest returns a hint for an unknown field name: a similarly named field,
+// or a reminder to quote text values such as 居酒屋.
+func (p *parser) suggest(name string) string {
+	for _, r := range name {
+		if r > 0x7F {
+			return fmt.Sprintf(" (quote text values: \"%s\")", name)
+		}
+	}
+	best, bestScore := "", 0
+	for f := range p.fields {
+		score := commonPrefix(f, name)
+		if strings.Contains(f, name) || strings.Contains(name, f) {
+			score += 3
+		}
+		if score > bestScore || score == bestScore && f < best {
+			best, bestScore = f, score
+		}
+	}
+	if bestScore < 3 {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64)
+}
+
+// --- nodes ---
+
+type literal struct {
+	k Kind
+	v Value
+	s span
+}
+
+func (n *literal) kind() Kind     { return n.k }
+func (n *literal) span() span     { return n.s }
+func (n *literal) eval(Env) Value { return n.v }
+
+type parenNode struct {
+	node node
+	s    span
+}
+
+func (n *parenNode) kind() Kind         { return n.node.kind() }
+func (n *parenNode) span() span         { return n.s }
+func (n *parenNode) eval(env Env) Value { return n.node.eval(env) }
+
+type fieldNode struct {
+	name string
+	k    Kind
+	s    span
+}
+
+func (n *fieldNode) kind() Kind         { return n.k }
+func (n *fieldNode) span() span         { return n.s }
+func (n *fieldNode) eval(env Env) Value { return env(n.name) }
+
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) kind() Kind { return Bool }
+func (n *logicalNode) span() span { return span{n.left.span().pos, n.right.span().end} }
+func (n *logicalNode) eval(env Env) Value {
+	l := n.left.eval(env).Bool
+	if n.or && l || !n.or && !l {
+		return Value{Bool: l}
+	}
+	return Value{Bool: n.right.eval(env).Bool}
+}
+
+type notNode struct {
+	pos     int
+	operand node
+}
+
+func (n *notNode) kind() Kind         { return Bool }
+func (n *notNode) span() span         { return span{n.pos, n.operand.span().end} }
+func (n *notNode) eval(env Env) Value { return Value{Bool: !n.operand.eval(env).Bool} }
+
+type compareNode struct {
+	op          string
+	operands    Kind
+	left, right node
+}
+
+func (n *compareNode) kind() Kind { return Bool }
+func (n *compareNode) span() span { return span{n.left.span().pos, n.right.span().end} }
+func (n *compareNode) eval(env Env) Value {
+	l, r := n.left.eval(env), n.right.eval(env)
+	var c int
+	switch n.operands {
+	case Number:
+		switch {
+		case l.Num < r.Num:
+			c = -1
+		case l.Num > r.Num:
+			c = 1
+		}
+	case String:
+		c = strings.Compare(l.Str, r.Str)
+	case Bool:
+		if l.Bool != r.Bool {
+			c = 1
+		}
+	}
+	switch n.op {
+	case "==":
+		return Value{Bool: c == 0}
+	case "!=":
+		return Value{Bool: c != 0}
+	case "<":
+		return Value{Bool: c < 0}
+	case "<=":
+		return Value{Bool: c <= 0}
+	case ">":
+		return Value{Bool: c > 0}
+	default:
+		return Value{Bool: c >= 0}
+	}
+}
+
+type containsNode struct{ left, right node }
+
+func (n *containsNode) kind() Kind { return Bool }
+func (n *containsNode) span() span { return span{n.left.span().pos, n.right.span().end} }
+func (n *containsNode) eval(env Env) Value {
+	l, r := strings.ToLower(n.left.eval(env).Str), strings.ToLower(n.right.eval(env).Str)
+	return Value{Bool: strings.Contains(l, r)}
+}
+
+type matchNode struct {
+	negate bool
+	left   node
+	re     *regexp.Regexp
+	end    int
+}
+
+func (n *matchNode) kind() Kind { return Bool }
+func (n *matchNode) span() span { return span{n.left.span().pos, n.end} }
+func (n *matchNode) eval(env Env) Value {
+	return Value{Bool: n.re.MatchString(n.left.eval(env).Str) != n.negate}
+}
+
+type inNode struct {
+	negate bool
+	left   node
+	strs   []string
+	nums   []float64
+	end    int
+}
+
+func (n *inNode) kind() Kind { return Bool }
+func (n *inNode) span() span { return span{n.left.span().pos, n.end} }
+func (n *inNode) eval(env Env) Value {
+	v := n.left.eval(env)
+	found := false
+	for _, s := range n.strs {
+		found = found || s == v.Str
+	}
+	for _, f := range n.nums {
+		found = found || f == v.Num
+	}
+	return Value{Bool: found != n.negate}
+}
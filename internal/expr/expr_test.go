@@ -0,0 +1,125 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+var testFields = map[string]Kind{
+	"capacity":    Number,
+	"budget":      Number,
+	"genre":       String,
+	"name":        String,
+	"non_smoking": Flag,
+	"karaoke":     Flag,
+	"open":        Bool,
+}
+
+func testEnv(name string) Value {
+	switch name {
+	case "capacity":
+		return Value{Num: 45}
+	case "budget":
+		return Value{Num: 3500}
+	case "genre":
+		return Value{Str: "ダイニングバー"}
+	case "name":
+		return Value{Str: "Bar Shiba"}
+	case "non_smoking":
+		return Value{Str: "全面禁煙", Bool: true}
+	case "karaoke":
+		return Value{Str: "なし", Bool: false}
+	case "open":
+		return Value{Bool: true}
+	}
+	return Value{}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`capacity >= 40`, true},
+		{`capacity > 45`, false},
+		{`capacity = 45`, true},
+		{`budget < 4000円`, true},
+		{`capacity >= 40 and non_smoking == "全面禁煙" and budget < 4000 and genre != "居酒屋"`, true},
+		{`not karaoke`, true},
+		{`!karaoke && non_smoking`, true},
+		{`karaoke or capacity < 10`, false},
+		{`(karaoke or capacity > 10) and open`, true},
+		{`open == true`, true},
+		{`name contains "shiba"`, true},
+		{`name =~ "^Bar "`, true},
+		{`name matches "(?i)^bar"`, true},
+		{`name !~ "Izakaya"`, true},
+		{`genre in ["居酒屋", "ダイニングバー"]`, true},
+		{`genre not in ["居酒屋"]`, true},
+		{`capacity in [10, 45]`, true},
+		{`NOT (capacity >= 40 AND open)`, false},
+	}
+	for _, tt := range tests {
+		e, err := Compile(tt.src, testFields)
+		if err != nil {
+			t.Fatalf("Compile(%q): unexpected error: %v", tt.src, err)
+		}
+		if got := e.Eval(testEnv); got != tt.want {
+			t.Fatalf("Eval(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src   string
+		msg   string
+		caret string // the underlined source text
+	}{
+		{`capcity >= 40`, `unknown field "capcity"`, "capcity"},
+		{`capacity >= "forty"`, `>= needs numbers`, ">="},
+		{`genre == 居酒屋`, `quote text values`, "居酒屋"},
+		{`capacity >= 40 and`, `expected a field or value, got end of expression`, ""},
+		{`capacity`, `expected a condition, got a number`, "capacity"},
+		{`name =~ "("`, `invalid regular expression`, `"("`},
+		{`genre in ["a", 1]`, `expected a string in list`, "1"},
+		{`(open`, `expected ), got end of expression`, ""},
+		{`name == "x`, `unterminated string`, `"x`},
+		{`open $ 1`, `unexpected character '$'`, "$"},
+		{`not budget`, `"not" needs a condition, got a number`, "budget"},
+		{`open open`, `unexpected "open"`, "open"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src, testFields)
+		if err == nil {
+			t.Fatalf("Compile(%q): expected error", tt.src)
+		}
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Compile(%q): expected *Error, got %T", tt.src, err)
+		}
+		if !strings.Contains(e.Msg, tt.msg) {
+			t.Fatalf("Compile(%q): error %q does not contain %q", tt.src, e.Msg, tt.msg)
+		}
+		if got := tt.src[e.Pos:min(e.End, len(tt.src))]; got != tt.caret {
+			t.Fatalf("Compile(%q): error points at %q, want %q", tt.src, got, tt.caret)
+		}
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	_, err := Compile(`genre == "x" and capcity > 1`, testFields)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected message, source and caret lines, got %q", err.Error())
+	}
+	if !strings.Contains(lines[0], "column 18") || !strings.Contains(lines[0], `did you mean "capacity"?`) {
+		t.Fatalf("unexpected message line %q", lines[0])
+	}
+	if lines[2] != "  "+strings.Repeat(" ", 17)+"^^^^^^^" {
+		t.Fatalf("unexpected caret line %q", lines[2])
+	}
+}
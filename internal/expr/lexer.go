@@ -0,0 +1,132 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp     // == != < <= > >= =~ !~ && || !
+	tokLParen // (
+	tokRParen // )
+	tokLBrack // [
+	tokRBrack // ]
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string // raw text; for strings, the unquoted value
+	pos  int    // byte offset in source
+	end  int    // byte offset just past the token
+}
+
+// keyword reports whether t is the given bare word, case-insensitively.
+func (t token) keyword(word string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, word)
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", "="}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == ',':
+			kind := map[rune]tokenKind{'(': tokLParen, ')': tokRParen, '[': tokLBrack, ']': tokRBrack, ',': tokComma}[r]
+			toks = append(toks, token{kind: kind, text: string(r), pos: i, end: i + 1})
+			i++
+		case r == '"' || r == '\'':
+			tok, err := lexString(src, i, r)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, tok)
+			i = tok.end
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == '_') {
+				j++
+			}
+			// Allow a trailing 円 on money literals: budget < 4000円
+			text := src[i:j]
+			if strings.HasPrefix(src[j:], "円") {
+				j += len("円")
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, pos: i, end: j})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(src) {
+				r, size := utf8.DecodeRuneInString(src[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i, end: j})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &Error{Src: src, Pos: i, End: i + size, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			if op == "=" {
+				op = "=="
+				toks = append(toks, token{kind: tokOp, text: op, pos: i, end: i + 1})
+				i++
+				continue
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, pos: len(src), end: len(src)})
+	return toks, nil
+}
+
+func lexString(src string, start int, quote rune) (token, error) {
+	var b strings.Builder
+	i := start + 1
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r == quote:
+			return token{kind: tokString, text: b.String(), pos: start, end: i + size}, nil
+		case r == '\\' && i+1 < len(src):
+			next, nsize := utf8.DecodeRuneInString(src[i+1:])
+			b.WriteRune(next)
+			i += 1 + nsize
+			continue
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	return token{}, &Error{Src: src, Pos: start, End: len(src), Msg: "unterminated string"}
+}
@@ -0,0 +1,38 @@
+package expr
+
+import "testing"
+
+func TestLex(t *testing.T) {
+	toks, err := lex(`capacity>=40 and genre != '居酒屋' or name =~ "a\"b" && budget<3,500円`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []struct {
+		kind tokenKind
+		text string
+	}{
+		{tokIdent, "capacity"}, {tokOp, ">="}, {tokNumber, "40"},
+		{tokIdent, "and"}, {tokIdent, "genre"}, {tokOp, "!="}, {tokString, "居酒屋"},
+		{tokIdent, "or"}, {tokIdent, "name"}, {tokOp, "=~"}, {tokString, `a"b`},
+		{tokOp, "&&"}, {tokIdent, "budget"}, {tokOp, "<"}, {tokNumber, "3"},
+		{tokComma, ","}, {tokNumber, "500"}, {tokEOF, ""},
+	}
+	if len(toks) != len(want) {
+		t.Fatalf("expected %d tokens, got %d: %+v", len(want), len(toks), toks)
+	}
+	for i, w := range want {
+		if toks[i].kind != w.kind || toks[i].text != w.text {
+			t.Fatalf("token %d: got %+v, want %+v", i, toks[i], w)
+		}
+	}
+}
+
+func TestLex_Positions(t *testing.T) {
+	toks, err := lex(`a == "値"`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if toks[2].pos != 5 || toks[2].end != 10 {
+		t.Fatalf("expected string token at bytes 5-10, got %d-%d", toks[2].pos, toks[2].end)
+	}
+}
@@ -0,0 +1,117 @@
+package fav
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/jsonfile"
+)
+
+// ErrNotFound is returned for a shop ID that is not a favorite.
+var ErrNotFound = errors.New("not in favorites")
+
+// Favorite is a shop kept locally with personal notes.
+type Favorite struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Note    string    `json:"note,omitempty"`
+	Tags    []string  `json:"tags,omitempty"`
+	Rating  int       `json:"rating,omitempty"` // 1-5; 0 when unrated
+	AddedAt time.Time `json:"added_at"`
+}
+
+// HasTag reports whether f is tagged tag, ignoring case.
+func (f *Favorite) HasTag(tag string) bool {
+	return slices.ContainsFunc(f.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// AddTags adds the tags f does not have yet.
+func (f *Favorite) AddTags(tags ...string) {
+	for _, t := range tags {
+		if t = strings.TrimSpace(t); t != "" && !f.HasTag(t) {
+			f.Tags = append(f.Tags, t)
+		}
+	}
+}
+
+// Stars renders a rating as five stars, e.g. "★★★☆☆", or "" when unrated.
+func Stars(rating int) string {
+	if rating <= 0 {
+		return ""
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", max(5-rating, 0))
+}
+
+// ValidRating reports whether r is a rating from 1 to 5.
+func ValidRating(r int) error {
+	if r < 1 || r > 5 {
+		return fmt.Errorf("rating must be from 1 to 5, got %d", r)
+	}
+	return nil
+}
+
+// Store keeps favorites in a JSON file, in the order they were added.
+type Store struct {
+	Path string
+}
+
+type favFile struct {
+	Favorites []Favorite `json:"favorites"`
+}
+
+func (s *Store) file() *jsonfile.File {
+	return &jsonfile.File{Path: s.Path, Name: "favorites"}
+}
+
+// List returns all favorites.
+func (s *Store) List() ([]Favorite, error) {
+	var f favFile
+	if err := s.file().Read(&f); err != nil {
+		return nil, err
+	}
+	return f.Favorites, nil
+}
+
+// Index returns the favorites keyed by shop ID.
+func (s *Store) Index() (map[string]*Favorite, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]*Favorite, len(list))
+	for i := range list {
+		idx[list[i].ID] = &list[i]
+	}
+	return idx, nil
+}
+
+// Put adds f, or replaces the favorite with the same ID in place.
+func (s *Store) Put(f Favorite) error {
+	var file favFile
+	return s.file().Update(&file, func() error {
+		if i := slices.IndexFunc(file.Favorites, func(x Favorite) bool { return x.ID == f.ID }); i >= 0 {
+			file.Favorites[i] = f
+		} else {
+			file.Favorites = append(file.Favorites, f)
+		}
+		return nil
+	})
+}
+
+// Remove deletes the favorites with the given IDs. It fails without
+// changing anything if one of them is not a favorite.
+func (s *Store) Remove(ids ...string) error {
+	var file favFile
+	return s.file().Update(&file, func() error {
+		for _, id := range ids {
+			if !slices.ContainsFunc(file.Favorites, func(x Favorite) bool { return x.ID == id }) {
+				return fmt.Errorf("%s: %w", id, ErrNotFound)
+			}
+		}
+		file.Favorites = slices.DeleteFunc(file.Favorites, func(x Favorite) bool { return slices.Contains(ids, x.ID) })
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package fav
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	s := &Store{Path: filepath.Join(t.TempDir(), "hpp", "favorites.json")}
+	if err := s.Put(Favorite{ID: "J2", Name: "ラーメン大門"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Put(Favorite{ID: "J1", Name: "浜松町酒場", Rating: 4}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Put(Favorite{ID: "J2", Name: "ラーメン大門", Note: "fast"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "J2" || list[0].Note != "fast" || list[1].Rating != 4 {
+		t.Fatalf("expected J2 updated in place before J1, got %+v", list)
+	}
+	idx, err := s.Index()
+	if err != nil || idx["J1"] == nil || idx["J3"] != nil {
+		t.Fatalf("unexpected index %v %v", idx, err)
+	}
+
+	if err := s.Remove("J1", "J3"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if list, _ := s.List(); len(list) != 2 {
+		t.Fatalf("expected a failed remove to change nothing, got %+v", list)
+	}
+	if err := s.Remove("J1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list, _ := s.List(); len(list) != 1 || list[0].ID != "J2" {
+		t.Fatalf("expected only J2 left, got %+v", list)
+	}
+}
+
+func TestTags(t *testing.T) {
+	f := Favorite{Tags: []string{"Lunch"}}
+	f.AddTags("lunch", " cheap ", "", "date")
+	if len(f.Tags) != 3 || f.Tags[1] != "cheap" {
+		t.Fatalf("expected tags added once, got %q", f.Tags)
+	}
+	if !f.HasTag("LUNCH") || f.HasTag("dinner") {
+		t.Fatalf("unexpected HasTag results for %q", f.Tags)
+	}
+}
+
+func TestStars(t *testing.T) {
+	if got := Stars(3); got != "★★★☆☆" {
+		t.Fatalf("expected 3 of 5 stars, got %q", got)
+	}
+	if Stars(0) != "" {
+		t.Fatal("expected no stars when unrated")
+	}
+	if ValidRating(0) == nil || ValidRating(6) == nil || ValidRating(5) != nil {
+		t.Fatal("unexpected rating validation")
+	}
+}
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Result is the outcome of Collect.
+type Result struct {
+	Shops     []api.Shop
+	Scanned   int  // shops fetched and tested against the filters
+	Available int  // results_available reported by the API, before filtering
+	Exhausted bool // every result from the start position was scanned
+	NextStart int  // unfiltered start position to continue from
+}
+
+// Estimate returns the expected number of matches among all available
+// results, extrapolated from the match rate of the scanned ones. It is exact
+// when the scan started at 1 and was exhausted.
+func (r *Result) Estimate() int {
+	if r.Exhausted || r.Scanned == 0 {
+		return len(r.Shops)
+	}
+	return int(float64(r.Available)*float64(len(r.Shops))/float64(r.Scanned) + 0.5)
+}
+
+// Collect pages through /gourmet/v1/ results from p.Start, parses access
+// text and keeps shops accepted by every filter until want matches are found
+// or maxPages pages of MaxGourmetCount have been scanned.
+func Collect(c *api.Client, p api.GourmetSearchParams, want, maxPages int, filters ...Func) (*Result, error) {
+	res := &Result{NextStart: 1}
+	if p.Start != nil {
+		res.NextStart = *p.Start
+	}
+	err := c.GourmetPages(p, maxPages, func(r *api.GourmetResults) bool {
+		res.Available = r.ResultsAvailable
+		for i := range r.Shops {
+			s := &r.Shops[i]
+			s.ParseAccess()
+			res.Scanned++
+			res.NextStart++
+			if Match(s, filters...) {
+				res.Shops = append(res.Shops, *s)
+				if len(res.Shops) >= want {
+					res.Exhausted = res.NextStart > r.ResultsAvailable
+					return false
+				}
+			}
+		}
+		res.Exhausted = len(r.Shops) == 0 || res.NextStart > r.ResultsAvailable
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/apitest"
+)
+
+// newPagedClient returns a client for a fake API with total shops; every
+// third shop (J003, J006, ...) is a karaoke bar.
+func newPagedClient(t *testing.T, total int) *api.Client {
+	fake := &apitest.Paged{Total: total, Item: func(_ string, i int) string {
+		karaoke := "なし"
+		if i%3 == 0 {
+			karaoke = "あり"
+		}
+		return fmt.Sprintf(`{"id":"J%03d","karaoke":%q}`, i, karaoke)
+	}}
+	c := api.NewClient("k")
+	c.BaseURL = fake.Start(t)
+	return c
+}
+
+func karaokeOnly(s *api.Shop) bool { return s.Karaoke == "あり" }
+
+func TestCollect_StopsAtWant(t *testing.T) {
+	c := newPagedClient(t, 1000)
+	res, err := Collect(c, api.GourmetSearchParams{}, 5, 0, karaokeOnly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Shops) != 5 || res.Shops[4].ID != "J015" {
+		t.Fatalf("expected first 5 karaoke shops, got %v", ids(res.Shops))
+	}
+	if res.Scanned != 15 || res.NextStart != 16 || res.Exhausted {
+		t.Fatalf("unexpected scan state %+v", res)
+	}
+	if got := res.Estimate(); got != 333 {
+		t.Fatalf("expected estimate 333, got %d", got)
+	}
+}
+
+func TestCollect_MaxPages(t *testing.T) {
+	c := newPagedClient(t, 1000)
+	res, err := Collect(c, api.GourmetSearchParams{}, 100, 1, karaokeOnly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Shops) != 33 || res.Scanned != 100 || res.Exhausted || res.NextStart != 101 {
+		t.Fatalf("expected one page scanned, got %d shops, state %+v", len(res.Shops), res)
+	}
+}
+
+func TestCollect_Exhausted(t *testing.T) {
+	c := newPagedClient(t, 10)
+	start := 4
+	res, err := Collect(c, api.GourmetSearchParams{Start: &start}, 50, 0, karaokeOnly)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Exhausted || res.Estimate() != 2 {
+		t.Fatalf("expected exhausted scan with 2 matches, got %v %+v", ids(res.Shops), res)
+	}
+	if res.Shops[0].ID != "J006" {
+		t.Fatalf("expected scan to begin at start, got %v", ids(res.Shops))
+	}
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// ExcludeGenre drops shops whose genre or sub-genre code or name is in
+// genres (e.g. "G001" or "居酒屋").
+func ExcludeGenre(genres []string) Func {
+	return func(s *api.Shop) bool {
+		return !matchesAny(genres, s.Genre.Code, s.Genre.Name, s.SubGenre.Code, s.SubGenre.Name)
+	}
+}
+
+// ExcludeArea drops shops in any of areas, given as a code or name at any
+// level from service area down to small area.
+func ExcludeArea(areas []string) Func {
+	return func(s *api.Shop) bool {
+		return !matchesAny(areas,
+			s.ServiceArea.Code, s.ServiceArea.Name,
+			s.LargeArea.Code, s.LargeArea.Name,
+			s.MiddleArea.Code, s.MiddleArea.Name,
+			s.SmallArea.Code, s.SmallArea.Name)
+	}
+}
+
+// ExcludeKeyword drops shops mentioning any of words in the fields the API
+// keyword search covers: name, kana, address, station, genre and catch copy.
+func ExcludeKeyword(words []string) Func {
+	return func(s *api.Shop) bool {
+		text := strings.ToLower(strings.Join([]string{
+			s.Name, s.NameKana, s.Address, s.StationName,
+			s.Genre.Name, s.SubGenre.Name, s.Catch,
+		}, "\n"))
+		for _, w := range words {
+			if w = strings.ToLower(strings.TrimSpace(w)); w != "" && strings.Contains(text, w) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ExcludeIDs drops the shops with one of the given IDs.
+func ExcludeIDs(ids []string) Func {
+	keep := IDs(ids)
+	return func(s *api.Shop) bool { return !keep(s) }
+}
+
+// Without drops shops that offer the amenity.
+func Without(a api.Amenity) Func {
+	return func(s *api.Shop) bool {
+		return !api.HasAmenity(a.Shop(s))
+	}
+}
+
+func matchesAny(wanted []string, values ...string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if v != "" && strings.EqualFold(v, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestExclusions(t *testing.T) {
+	shops := []api.Shop{
+		{ID: "izakaya", Genre: api.CodeName{Code: "G001", Name: "居酒屋"}, MiddleArea: api.CodeName{Code: "Y005", Name: "新橋・汐留"}, Karaoke: "あり"},
+		{ID: "bar", Genre: api.CodeName{Code: "G002", Name: "ダイニングバー"}, SubGenre: api.CodeName{Code: "G001", Name: "居酒屋"}, Karaoke: "なし", Catch: "夜景が自慢"},
+		{ID: "ramen", Name: "麺屋 Ramen", Genre: api.CodeName{Code: "G013", Name: "ラーメン"}, SmallArea: api.CodeName{Code: "X085", Name: "浜松町"}},
+	}
+	var karaoke api.Amenity
+	for _, a := range api.Amenities {
+		if a.Name == "karaoke" {
+			karaoke = a
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    Func
+		want string
+	}{
+		{"genre by code includes sub-genre", ExcludeGenre([]string{"G001"}), "ramen"},
+		{"genre by name", ExcludeGenre([]string{"ラーメン"}), "izakaya,bar"},
+		{"middle area code", ExcludeArea([]string{"Y005"}), "bar,ramen"},
+		{"small area name", ExcludeArea([]string{"浜松町"}), "izakaya,bar"},
+		{"keyword in catch", ExcludeKeyword([]string{"夜景"}), "izakaya,ramen"},
+		{"keyword case-insensitive", ExcludeKeyword([]string{"RAMEN"}), "izakaya,bar"},
+		{"without amenity", Without(karaoke), "bar,ramen"},
+		{"IDs", ExcludeIDs([]string{"bar", "sushi"}), "izakaya,ramen"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(ids(Apply(shops, tt.f)), ","); got != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
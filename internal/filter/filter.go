@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+)
+
+// Func reports whether a shop should be kept.
+type Func func(s *api.Shop) bool
+
+// Apply returns the shops accepted by every filter, preserving order.
+func Apply(shops []api.Shop, filters ...Func) []api.Shop {
+	if len(filters) == 0 {
+		return shops
+	}
+	kept := make([]api.Shop, 0, len(shops))
+	for i := range shops {
+		if Match(&shops[i], filters...) {
+			kept = append(kept, shops[i])
+		}
+	}
+	return kept
+}
+
+// Match reports whether s is accepted by every filter.
+func Match(s *api.Shop, filters ...Func) bool {
+	for _, f := range filters {
+		if !f(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// IDs keeps the shops with one of the given IDs.
+func IDs(ids []string) Func {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return func(s *api.Shop) bool { return set[s.ID] }
+}
+
+// Walk keeps shops with an access route from station (any station when
+// empty) within maxMinutes on foot (any distance when 0). Shops must have had
+// ParseAccess called. Routes without a stated walking time never satisfy a
+// maxMinutes limit.
+func Walk(station string, maxMinutes int) Func {
+	names := stationNames(station)
+	return func(s *api.Shop) bool {
+		for _, r := range s.AccessRoutes {
+			if len(names) > 0 && !matchesStation(r.Station, names) {
+				continue
+			}
+			if maxMinutes > 0 && (r.WalkMinutes == 0 || r.WalkMinutes > maxMinutes) {
+				continue
+			}
+			return true
+		}
+		return false
+	}
+}
+
+// stationNames returns the spellings a station query should match. Romaji
+// queries such as "hamamatsucho" are resolved to kanji via the built-in
+// station dataset, since access text is always in Japanese.
+func stationNames(query string) []string {
+	query = strings.TrimSuffix(strings.TrimSpace(query), "駅")
+	if query == "" {
+		return nil
+	}
+	names := []string{query}
+	if isASCII(query) {
+		if p, err := geo.Resolve(query); err == nil {
+			names = append(names, strings.TrimSuffix(p.Name, "駅"))
+		}
+	}
+	return names
+}
+
+// matchesStation reports whether station is one of names. Names are
+// compared whole, without a trailing 駅, so 新橋 matches 新橋駅 but not
+// 新橋本町.
+func matchesStation(station string, names []string) bool {
+	station = strings.TrimSuffix(strings.TrimSpace(station), "駅")
+	if station == "" {
+		return false
+	}
+	return slices.Contains(names, station)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func shopWithAccess(id, access string) api.Shop {
+	s := api.Shop{ID: id, Access: access}
+	s.ParseAccess()
+	return s
+}
+
+func ids(shops []api.Shop) []string {
+	var out []string
+	for _, s := range shops {
+		out = append(out, s.ID)
+	}
+	return out
+}
+
+func TestApply_NoFilters(t *testing.T) {
+	shops := []api.Shop{{ID: "a"}, {ID: "b"}}
+	if got := Apply(shops); len(got) != 2 {
+		t.Fatalf("expected all shops, got %v", ids(got))
+	}
+}
+
+func TestIDs(t *testing.T) {
+	shops := []api.Shop{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	got := ids(Apply(shops, IDs([]string{"c", "a", "x"})))
+	if len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Fatalf("IDs kept %v, want [a c]", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	shops := []api.Shop{
+		shopWithAccess("near", "JR浜松町駅北口より徒歩3分"),
+		shopWithAccess("far", "浜松町駅から徒歩12分"),
+		shopWithAccess("other", "新橋駅から徒歩2分"),
+		shopWithAccess("multi", "新橋駅から徒歩9分／浜松町駅から徒歩4分"),
+		shopWithAccess("unknown", "浜松町駅前"),
+		shopWithAccess("prefix", "新橋本町駅から徒歩1分"),
+	}
+
+	tests := []struct {
+		station string
+		max     int
+		want    []string
+	}{
+		{"", 5, []string{"near", "other", "multi", "prefix"}},
+		{"浜松町", 0, []string{"near", "far", "multi", "unknown"}},
+		{"浜松町駅", 5, []string{"near", "multi"}},
+		{"hamamatsucho", 5, []string{"near", "multi"}},
+		{"新橋", 0, []string{"other", "multi"}},
+		{"新橋本町", 0, []string{"prefix"}},
+		{"橋", 0, nil},
+	}
+	for _, tt := range tests {
+		got := ids(Apply(shops, Walk(tt.station, tt.max)))
+		if len(got) != len(tt.want) {
+			t.Fatalf("Walk(%q, %d) = %v, want %v", tt.station, tt.max, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("Walk(%q, %d) = %v, want %v", tt.station, tt.max, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"math"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/expr"
+)
+
+type whereField struct {
+	kind expr.Kind
+	get  func(s *api.Shop) expr.Value
+}
+
+func num(f func(s *api.Shop) float64) whereField {
+	return whereField{expr.Number, func(s *api.Shop) expr.Value { return expr.Value{Num: f(s)} }}
+}
+
+func str(f func(s *api.Shop) string) whereField {
+	return whereField{expr.String, func(s *api.Shop) expr.Value { return expr.Value{Str: f(s)} }}
+}
+
+// whereFields are the Shop fields available to --where expressions.
+var whereFields = map[string]whereField{
+	"id":                str(func(s *api.Shop) string { return s.ID }),
+	"name":              str(func(s *api.Shop) string { return s.Name }),
+	"name_kana":         str(func(s *api.Shop) string { return s.NameKana }),
+	"address":           str(func(s *api.Shop) string { return s.Address }),
+	"station":           str(func(s *api.Shop) string { return s.StationName }),
+	"access":            str(func(s *api.Shop) string { return s.Access }),
+	"catch":             str(func(s *api.Shop) string { return s.Catch }),
+	"open":              str(func(s *api.Shop) string { return s.Open }),
+	"close":             str(func(s *api.Shop) string { return s.Close }),
+	"genre":             str(func(s *api.Shop) string { return s.Genre.Name }),
+	"genre_code":        str(func(s *api.Shop) string { return s.Genre.Code }),
+	"sub_genre":         str(func(s *api.Shop) string { return s.SubGenre.Name }),
+	"sub_genre_code":    str(func(s *api.Shop) string { return s.SubGenre.Code }),
+	"budget_code":       str(func(s *api.Shop) string { return s.Budget.Code }),
+	"budget_name":       str(func(s *api.Shop) string { return s.Budget.Name }),
+	"service_area":      str(func(s *api.Shop) string { return s.ServiceArea.Name }),
+	"service_area_code": str(func(s *api.Shop) string { return s.ServiceArea.Code }),
+	"large_area":        str(func(s *api.Shop) string { return s.LargeArea.Name }),
+	"large_area_code":   str(func(s *api.Shop) string { return s.LargeArea.Code }),
+	"middle_area":       str(func(s *api.Shop) string { return s.MiddleArea.Name }),
+	"middle_area_code":  str(func(s *api.Shop) string { return s.MiddleArea.Code }),
+	"small_area":        str(func(s *api.Shop) string { return s.SmallArea.Name }),
+	"small_area_code":   str(func(s *api.Shop) string { return s.SmallArea.Code }),
+
+	"capacity":       num(func(s *api.Shop) float64 { return float64(s.Capacity) }),
+	"party_capacity": num(func(s *api.Shop) float64 { return float64(s.PartyCapacity) }),
+	"budget":         num(func(s *api.Shop) float64 { return float64(s.Budget.AverageYen()) }),
+	"budget_min":     num(func(s *api.Shop) float64 { lo, _ := s.Budget.Range(); return float64(lo) }),
+	"budget_max":     num(func(s *api.Shop) float64 { _, hi := s.Budget.Range(); return float64(hi) }),
+	"walk":           num(walkMinutes),
+	"lat":            num(func(s *api.Shop) float64 { return s.Lat }),
+	"lng":            num(func(s *api.Shop) float64 { return s.Lng }),
+}
+
+func init() {
+	for _, a := range api.Amenities {
+		get := a.Shop
+		whereFields[a.Name] = whereField{expr.Flag, func(s *api.Shop) expr.Value {
+			v := get(s)
+			return expr.Value{Str: v, Bool: api.HasAmenity(v)}
+		}}
+	}
+}
+
+// walkMinutes is the shortest stated walk from any station, or +Inf when
+// unknown so that "walk <= 5" excludes it.
+func walkMinutes(s *api.Shop) float64 {
+	best := math.Inf(1)
+	for _, r := range s.AccessRoutes {
+		if r.WalkMinutes > 0 {
+			best = min(best, float64(r.WalkMinutes))
+		}
+	}
+	return best
+}
+
+// Where compiles a --where expression into a filter. Shops must have had
+// ParseAccess called for the walk field to be known.
+func Where(src string) (Func, error) {
+	kinds := make(map[string]expr.Kind, len(whereFields))
+	for name, f := range whereFields {
+		kinds[name] = f.kind
+	}
+	e, err := expr.Compile(src, kinds)
+	if err != nil {
+		return nil, err
+	}
+	return func(s *api.Shop) bool {
+		return e.Eval(func(name string) expr.Value { return whereFields[name].get(s) })
+	}, nil
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestWhere(t *testing.T) {
+	shops := []api.Shop{
+		{ID: "big-izakaya", Capacity: 80, Genre: api.CodeName{Name: "居酒屋"}, NonSmoking: "全面禁煙", Budget: api.Budget{Average: "3000円"}},
+		{ID: "big-bar", Capacity: 50, Genre: api.CodeName{Name: "ダイニングバー"}, NonSmoking: "全面禁煙", Budget: api.Budget{Average: "3500円"}},
+		{ID: "smoky-bar", Capacity: 60, Genre: api.CodeName{Name: "ダイニングバー"}, NonSmoking: "禁煙席なし", Budget: api.Budget{Average: "3000円"}},
+		{ID: "pricey", Capacity: 45, Genre: api.CodeName{Name: "和食"}, NonSmoking: "全面禁煙", Budget: api.Budget{Name: "5001～7000円"}},
+		{ID: "karaoke", Capacity: 100, Karaoke: "あり", Access: "新橋駅から徒歩2分"},
+	}
+	for i := range shops {
+		shops[i].ParseAccess()
+	}
+
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`capacity >= 40 and non_smoking == "全面禁煙" and budget < 4000 and genre != "居酒屋"`, "big-bar"},
+		{`not non_smoking and capacity > 0`, "smoky-bar,karaoke"},
+		{`karaoke`, "karaoke"},
+		{`budget_min > 5000`, "pricey"},
+		{`walk <= 5`, "karaoke"},
+		{`genre in ["居酒屋", "和食"]`, "big-izakaya,pricey"},
+	}
+	for _, tt := range tests {
+		f, err := Where(tt.src)
+		if err != nil {
+			t.Fatalf("Where(%q): unexpected error: %v", tt.src, err)
+		}
+		if got := strings.Join(ids(Apply(shops, f)), ","); got != tt.want {
+			t.Fatalf("Where(%q) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestWhere_Error(t *testing.T) {
+	if _, err := Where(`capacty > 1`); err == nil || !strings.Contains(err.Error(), "capacty") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const earthRadius = 6371000.0 // meters
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// ParsePoint parses "lat,lng" or resolves a station/landmark name. The
+// returned label is the place name, or the input for raw coordinates.
+func ParsePoint(s string) (Point, string, error) {
+	if lat, lng, ok := strings.Cut(s, ","); ok {
+		la, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+		ln, err2 := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+		if err1 == nil && err2 == nil {
+			if la < -90 || la > 90 || ln < -180 || ln > 180 {
+				return Point{}, "", fmt.Errorf("coordinates out of range: %s", s)
+			}
+			return Point{Lat: la, Lng: ln}, s, nil
+		}
+	}
+	p, err := Resolve(s)
+	if err != nil {
+		return Point{}, "", err
+	}
+	return Point{Lat: p.Lat, Lng: p.Lng}, p.Name, nil
+}
+
+// Distance returns the great-circle distance between a and b in meters.
+func Distance(a, b Point) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// Centroid returns the mean of points.
+func Centroid(points []Point) Point {
+	var c Point
+	for _, p := range points {
+		c.Lat += p.Lat
+		c.Lng += p.Lng
+	}
+	n := float64(len(points))
+	if n == 0 {
+		return c
+	}
+	return Point{Lat: c.Lat / n, Lng: c.Lng / n}
+}
+
+// Minimax returns the point minimizing the largest distance to any of points,
+// i.e. the center of their smallest enclosing circle. It uses the
+// Bădoiu–Clarkson iteration, which is accurate to a few meters at city scale.
+func Minimax(points []Point) Point {
+	if len(points) == 0 {
+		return Point{}
+	}
+	c := Centroid(points)
+	for i := 1; i <= 2000; i++ {
+		far, farDist := points[0], -1.0
+		for _, p := range points {
+			if d := Distance(c, p); d > farDist {
+				far, farDist = p, d
+			}
+		}
+		step := 1 / float64(i+1)
+		c.Lat += (far.Lat - c.Lat) * step
+		c.Lng += (far.Lng - c.Lng) * step
+	}
+	return c
+}
+
+// FormatDistance renders meters as "850m" or "1.2km".
+func FormatDistance(m float64) string {
+	if m < 1000 {
+		return fmt.Sprintf("%.0fm", m)
+	}
+	return fmt.Sprintf("%.1fkm", m/1000)
+}
@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tokyo := Point{Lat: 35.681236, Lng: 139.767125}
+	shinjuku := Point{Lat: 35.689592, Lng: 139.700413}
+	d := Distance(tokyo, shinjuku)
+	if d < 6000 || d > 6200 {
+		t.Fatalf("expected ~6.1km, got %.0fm", d)
+	}
+	if Distance(tokyo, tokyo) != 0 {
+		t.Fatal("expected zero distance to self")
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	p, label, err := ParsePoint("35.68, 139.76")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Lat != 35.68 || p.Lng != 139.76 || label != "35.68, 139.76" {
+		t.Fatalf("unexpected point %+v label %q", p, label)
+	}
+
+	p, label, err = ParsePoint("hamamatsucho")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label != "浜松町" || p.Lat == 0 {
+		t.Fatalf("unexpected point %+v label %q", p, label)
+	}
+
+	if _, _, err := ParsePoint("95,139"); err == nil {
+		t.Fatal("expected out-of-range error")
+	}
+}
+
+func TestCentroidAndMinimax(t *testing.T) {
+	// Two close points and one far away: the centroid is pulled towards the
+	// pair, the minimax point sits between the pair and the outlier.
+	pts := []Point{
+		{Lat: 35.0, Lng: 139.0},
+		{Lat: 35.0, Lng: 139.001},
+		{Lat: 35.0, Lng: 139.1},
+	}
+	c := Centroid(pts)
+	if math.Abs(c.Lng-139.0336667) > 1e-6 {
+		t.Fatalf("unexpected centroid %+v", c)
+	}
+
+	m := Minimax(pts)
+	worst := func(q Point) float64 {
+		w := 0.0
+		for _, p := range pts {
+			w = max(w, Distance(q, p))
+		}
+		return w
+	}
+	if worst(m) >= worst(c) {
+		t.Fatalf("expected minimax worst-case %.0f < centroid worst-case %.0f", worst(m), worst(c))
+	}
+	if math.Abs(m.Lng-139.05) > 0.001 {
+		t.Fatalf("expected minimax near midpoint of extremes, got %+v", m)
+	}
+}
+
+func TestFormatDistance(t *testing.T) {
+	if got := FormatDistance(850); got != "850m" {
+		t.Fatalf("got %s", got)
+	}
+	if got := FormatDistance(1234); got != "1.2km" {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -0,0 +1,189 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed places.json
+var placesJSON []byte
+
+// Place is a station or landmark from the embedded dataset.
+type Place struct {
+	Name    string   `json:"name"`
+	Kana    string   `json:"kana"`
+	Romaji  string   `json:"romaji"`
+	Aliases []string `json:"aliases,omitempty"`
+	Kind    string   `json:"kind"` // "station" or "landmark"
+	Lines   []string `json:"lines,omitempty"`
+	Lat     float64  `json:"lat"`
+	Lng     float64  `json:"lng"`
+}
+
+// Match is a place together with how well it matched a query (0-1].
+type Match struct {
+	Place
+	Score float64 `json:"score"`
+}
+
+// minScore is the lowest fuzzy score still considered a match.
+const minScore = 0.5
+
+var places = sync.OnceValue(func() []Place {
+	var ps []Place
+	if err := json.Unmarshal(placesJSON, &ps); err != nil {
+		panic(fmt.Sprintf("geo: invalid embedded places.json: %v", err))
+	}
+	return ps
+})
+
+// Places returns every place in the embedded dataset.
+func Places() []Place {
+	return places()
+}
+
+// Search returns places matching query, best match first. Names are compared
+// in kanji, kana (hiragana or katakana) and romaji, ignoring case, spacing,
+// long vowels and a trailing 駅/station. An empty query matches everything.
+func Search(query string) []Match {
+	q := normalize(query)
+	var matches []Match
+	for _, p := range Places() {
+		if q == "" {
+			matches = append(matches, Match{Place: p, Score: 1})
+			continue
+		}
+		best := 0.0
+		for _, key := range p.keys() {
+			if s := score(q, normalize(key)); s > best {
+				best = s
+			}
+		}
+		if best >= minScore {
+			matches = append(matches, Match{Place: p, Score: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
+
+// Resolve returns the best matching place for query.
+func Resolve(query string) (Place, error) {
+	matches := Search(query)
+	if strings.TrimSpace(query) == "" || len(matches) == 0 {
+		return Place{}, fmt.Errorf("no station or landmark matches %q (see 'hpp station search')", query)
+	}
+	return matches[0].Place, nil
+}
+
+func (p Place) keys() []string {
+	return append([]string{p.Name, p.Kana, p.Romaji}, p.Aliases...)
+}
+
+// score rates how well the normalized query q matches the normalized key k.
+func score(q, k string) float64 {
+	if k == "" {
+		return 0
+	}
+	qr, kr := []rune(q), []rune(k)
+	switch {
+	case q == k:
+		return 1
+	case strings.HasPrefix(k, q):
+		return 0.8 + 0.1*float64(len(qr))/float64(len(kr))
+	case strings.Contains(k, q):
+		return 0.6 + 0.1*float64(len(qr))/float64(len(kr))
+	}
+	d := levenshtein(qr, kr)
+	n := max(len(qr), len(kr))
+	sim := 1 - float64(d)/float64(n)
+	// Short keys tolerate no typos; "ueno" vs "ueda" is not a match.
+	if n < 4 || sim < 0.75 {
+		return 0
+	}
+	return 0.7 * sim
+}
+
+var suffixes = []string{"駅", "えき", "station", "eki"}
+
+var romajiReplacer = strings.NewReplacer(
+	"ou", "o", "oo", "o", "uu", "u",
+	"mb", "nb", "mp", "np",
+)
+
+// normalize folds a place name or query into a comparable form: full-width
+// ASCII to half-width, katakana to hiragana, lower case, no separators, and
+// long-vowel and n/m spelling variants of romaji collapsed.
+func normalize(s string) string {
+	var b strings.Builder
+	ascii := true
+	for _, r := range s {
+		switch {
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		case r >= 'ァ' && r <= 'ヶ':
+			r -= 0x60
+		}
+		switch r {
+		case 'ゖ':
+			r = 'け'
+		case 'ゕ':
+			r = 'か'
+		case 'ā', 'â':
+			r = 'a'
+		case 'ī', 'î':
+			r = 'i'
+		case 'ū', 'û':
+			r = 'u'
+		case 'ē', 'ê':
+			r = 'e'
+		case 'ō', 'ô':
+			r = 'o'
+		}
+		if unicode.IsSpace(r) || strings.ContainsRune("-_・'.", r) {
+			continue
+		}
+		if r > unicode.MaxASCII {
+			ascii = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	out := b.String()
+	for _, suf := range suffixes {
+		if trimmed := strings.TrimSuffix(out, suf); trimmed != out && trimmed != "" {
+			out = trimmed
+			break
+		}
+	}
+	if ascii {
+		out = romajiReplacer.Replace(out)
+	}
+	return out
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
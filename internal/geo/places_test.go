@@ -0,0 +1,80 @@
+package geo
+
+import "testing"
+
+func TestPlacesDataset(t *testing.T) {
+	ps := Places()
+	if len(ps) == 0 {
+		t.Fatal("expected embedded places")
+	}
+	for _, p := range ps {
+		if p.Name == "" || p.Kana == "" || p.Romaji == "" {
+			t.Fatalf("incomplete place: %+v", p)
+		}
+		if p.Kind != "station" && p.Kind != "landmark" {
+			t.Fatalf("unexpected kind %q for %s", p.Kind, p.Name)
+		}
+		if p.Lat < 24 || p.Lat > 46 || p.Lng < 122 || p.Lng > 146 {
+			t.Fatalf("coordinates outside Japan for %s: %f,%f", p.Name, p.Lat, p.Lng)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"浜松町", "浜松町"},
+		{"浜松町駅", "浜松町"},
+		{"はままつちょう", "浜松町"},
+		{"ハママツチョウ", "浜松町"},
+		{"Hamamatsucho", "浜松町"},
+		{"hamamatsu-cho station", "浜松町"},
+		{"Shinbashi", "新橋"},
+		{"Tōkyō", "東京"},
+		{"東京", "東京"},
+		{"市ヶ谷", "市ケ谷"},
+		{"shinjku", "新宿"},
+		{"Tokyo Tower", "東京タワー"},
+		{"スカイツリー", "東京スカイツリー"},
+	}
+	for _, tt := range tests {
+		p, err := Resolve(tt.query)
+		if err != nil {
+			t.Fatalf("Resolve(%q): unexpected error: %v", tt.query, err)
+		}
+		if p.Name != tt.want {
+			t.Fatalf("Resolve(%q) = %s, want %s", tt.query, p.Name, tt.want)
+		}
+	}
+}
+
+func TestResolve_NoMatch(t *testing.T) {
+	for _, q := range []string{"", "zzzzzz", "xyz"} {
+		if _, err := Resolve(q); err == nil {
+			t.Fatalf("Resolve(%q): expected error", q)
+		}
+	}
+}
+
+func TestSearch_Empty(t *testing.T) {
+	if got, want := len(Search("")), len(Places()); got != want {
+		t.Fatalf("expected all %d places, got %d", want, got)
+	}
+}
+
+func TestSearch_Ranking(t *testing.T) {
+	matches := Search("shinjuku")
+	if len(matches) < 2 {
+		t.Fatalf("expected several matches, got %d", len(matches))
+	}
+	if matches[0].Name != "新宿" {
+		t.Fatalf("expected exact match first, got %s", matches[0].Name)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Fatal("expected matches sorted by score")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package jsonfile reads and updates the JSON files hpp keeps in its config
+// directory, such as saved searches, favorites and API usage.
+package jsonfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	lockWait  = 5 * time.Second
+	lockStale = 30 * time.Second
+)
+
+// File is a JSON file shared by hpp processes. Name describes its contents
+// in errors, e.g. "favorites".
+type File struct {
+	Path string
+	Name string
+}
+
+// Read decodes the file into v. A missing file leaves v as it is.
+func (f *File) Read(v any) error {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", f.Name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("reading %s %s: %w", f.Name, f.Path, err)
+	}
+	return nil
+}
+
+// Update reads the file into v, calls change and writes v back, unless
+// change fails. The file is locked meanwhile, so updates from concurrent
+// processes are not lost.
+func (f *File) Update(v any, change func() error) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return fmt.Errorf("creating %s directory: %w", f.Name, err)
+	}
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := f.Read(v); err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", f.Name, err)
+	}
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", f.Name, err)
+	}
+	if err := os.Rename(tmp, f.Path); err != nil {
+		return fmt.Errorf("writing %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// lock creates the lock file next to the file, waiting while another
+// process holds it. Locks older than lockStale are assumed abandoned.
+func (f *File) lock() (unlock func(), err error) {
+	path := f.Path + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		lf, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = lf.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("locking %s: %w", f.Name, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("locking %s: %s is held by another process", f.Name, path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
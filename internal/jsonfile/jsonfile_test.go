@@ -0,0 +1,47 @@
+package jsonfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type counter struct {
+	N int `json:"n"`
+}
+
+func TestUpdate(t *testing.T) {
+	f := &File{Path: filepath.Join(t.TempDir(), "hpp", "n.json"), Name: "counter"}
+	var c counter
+	if err := f.Read(&c); err != nil || c.N != 0 {
+		t.Fatalf("expected a missing file to read as empty, got %+v %v", c, err)
+	}
+
+	// Concurrent updates are serialized by the lock, so none is lost.
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			var c counter
+			if err := f.Update(&c, func() error { c.N++; return nil }); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	if err := f.Read(&c); err != nil || c.N != 20 {
+		t.Fatalf("expected 20 updates, got %+v %v", c, err)
+	}
+
+	errStop := errors.New("stop")
+	if err := f.Update(&c, func() error { c.N = 0; return errStop }); err != errStop {
+		t.Fatalf("expected the change error, got %v", err)
+	}
+	if err := f.Read(&c); err != nil || c.N != 20 {
+		t.Fatalf("expected a failed change not to be written, got %+v %v", c, err)
+	}
+	if _, err := os.Stat(f.Path + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected lock to be released, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package master
+
+import (
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Rename is a code whose name changed between snapshots.
+type Rename struct {
+	Code    string `json:"code"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+// TableDiff lists the changes to one master table.
+type TableDiff struct {
+	Table   string         `json:"table"`
+	Added   []api.CodeName `json:"added,omitempty"`
+	Removed []api.CodeName `json:"removed,omitempty"`
+	Renamed []Rename       `json:"renamed,omitempty"`
+}
+
+// Empty reports whether the table is unchanged.
+func (d TableDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Renamed) == 0
+}
+
+// Diff compares every table of old and new by code and returns the tables
+// that changed, in Tables order. Entries keep the order of the snapshot
+// they come from.
+func Diff(old, new *Snapshot) []TableDiff {
+	diffs := []TableDiff{}
+	for _, t := range new.Tables() {
+		d := TableDiff{Table: t.Name}
+		before, after := old.Entries(t.Name), new.Entries(t.Name)
+		oldNames := make(map[string]string, len(before))
+		for _, e := range before {
+			oldNames[e.Code] = e.Name
+		}
+		newCodes := make(map[string]bool, len(after))
+		for _, e := range after {
+			newCodes[e.Code] = true
+			name, ok := oldNames[e.Code]
+			switch {
+			case !ok:
+				d.Added = append(d.Added, e)
+			case name != e.Name:
+				d.Renamed = append(d.Renamed, Rename{Code: e.Code, OldName: name, NewName: e.Name})
+			}
+		}
+		for _, e := range before {
+			if !newCodes[e.Code] {
+				d.Removed = append(d.Removed, e)
+			}
+		}
+		if !d.Empty() {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,32 @@
+package master
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestDiff(t *testing.T) {
+	old := &Snapshot{
+		Genres:      []api.Genre{{Code: "G001", Name: "居酒屋"}, {Code: "G002", Name: "ダイニングバー"}, {Code: "G003", Name: "創作料理"}},
+		MiddleAreas: []api.MiddleArea{{Code: "Y005", Name: "新橋・汐留"}},
+	}
+	new := &Snapshot{
+		Genres:      []api.Genre{{Code: "G001", Name: "居酒屋"}, {Code: "G002", Name: "ダイニングバー・バル"}, {Code: "G017", Name: "韓国料理"}},
+		MiddleAreas: []api.MiddleArea{{Code: "Y005", Name: "新橋・汐留"}},
+	}
+	got := Diff(old, new)
+	want := []TableDiff{{
+		Table:   "genre",
+		Added:   []api.CodeName{{Code: "G017", Name: "韓国料理"}},
+		Removed: []api.CodeName{{Code: "G003", Name: "創作料理"}},
+		Renamed: []Rename{{Code: "G002", OldName: "ダイニングバー", NewName: "ダイニングバー・バル"}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if d := Diff(new, new); len(d) != 0 {
+		t.Fatalf("expected no changes, got %+v", d)
+	}
+}
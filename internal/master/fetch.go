@@ -0,0 +1,148 @@
+package master
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// areaPageSize is the page size used for the paged area endpoints.
+const areaPageSize = 100
+
+// Fetch downloads every master table, paging through middle and small
+// areas, and returns them as a snapshot.
+func Fetch(c *api.Client) (*Snapshot, error) {
+	s := &Snapshot{FormatVersion: FormatVersion, FetchedAt: time.Now().UTC()}
+	version := s.apiVersion
+
+	var genres api.GenreResponse
+	if err := c.Get("/genre/v1/", nil, &genres); err != nil {
+		return nil, fmt.Errorf("fetching genres: %w", err)
+	}
+	s.Genres = genres.Results.Genres
+	version(genres.Results.APIVersion)
+
+	var budgets api.BudgetResponse
+	if err := c.Get("/budget/v1/", nil, &budgets); err != nil {
+		return nil, fmt.Errorf("fetching budgets: %w", err)
+	}
+	s.Budgets = budgets.Results.Budgets
+	version(budgets.Results.APIVersion)
+
+	var cards api.CreditCardResponse
+	if err := c.Get("/credit_card/v1/", nil, &cards); err != nil {
+		return nil, fmt.Errorf("fetching credit cards: %w", err)
+	}
+	s.CreditCards = cards.Results.CreditCards
+	version(cards.Results.APIVersion)
+
+	var specials api.SpecialResponse
+	if err := c.Get("/special/v1/", nil, &specials); err != nil {
+		return nil, fmt.Errorf("fetching specials: %w", err)
+	}
+	s.Specials = specials.Results.Specials
+	version(specials.Results.APIVersion)
+
+	var categories api.SpecialCategoryResponse
+	if err := c.Get("/special_category/v1/", nil, &categories); err != nil {
+		return nil, fmt.Errorf("fetching special categories: %w", err)
+	}
+	s.SpecialCategories = categories.Results.SpecialCategories
+	version(categories.Results.APIVersion)
+
+	if err := fetchAreas(c, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// FetchAreas downloads only the area tables.
+func FetchAreas(c *api.Client) (*Snapshot, error) {
+	s := &Snapshot{FormatVersion: FormatVersion, FetchedAt: time.Now().UTC()}
+	if err := fetchAreas(c, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func fetchAreas(c *api.Client, s *Snapshot) error {
+	version := s.apiVersion
+
+	var largeService api.LargeServiceAreaResponse
+	if err := c.Get("/large_service_area/v1/", nil, &largeService); err != nil {
+		return fmt.Errorf("fetching large service areas: %w", err)
+	}
+	s.LargeServiceAreas = largeService.Results.LargeServiceAreas
+	version(largeService.Results.APIVersion)
+
+	var service api.ServiceAreaResponse
+	if err := c.Get("/service_area/v1/", nil, &service); err != nil {
+		return fmt.Errorf("fetching service areas: %w", err)
+	}
+	s.ServiceAreas = service.Results.ServiceAreas
+	version(service.Results.APIVersion)
+
+	var large api.LargeAreaResponse
+	if err := c.Get("/large_area/v1/", nil, &large); err != nil {
+		return fmt.Errorf("fetching large areas: %w", err)
+	}
+	s.LargeAreas = large.Results.LargeAreas
+	version(large.Results.APIVersion)
+
+	middle, err := MiddleAreas(c, api.MiddleAreaParams{})
+	if err != nil {
+		return fmt.Errorf("fetching middle areas: %w", err)
+	}
+	s.MiddleAreas = middle
+
+	small, err := SmallAreas(c, api.SmallAreaParams{})
+	if err != nil {
+		return fmt.Errorf("fetching small areas: %w", err)
+	}
+	s.SmallAreas = small
+	return nil
+}
+
+// MiddleAreas pages through the middle areas matching p.
+func MiddleAreas(c *api.Client, p api.MiddleAreaParams) ([]api.MiddleArea, error) {
+	var out []api.MiddleArea
+	for start := 1; ; {
+		count := areaPageSize
+		p.Start, p.Count = &start, &count
+		var resp api.MiddleAreaResponse
+		if err := c.Get("/middle_area/v1/", p, &resp); err != nil {
+			return nil, err
+		}
+		out = append(out, resp.Results.MiddleAreas...)
+		start += len(resp.Results.MiddleAreas)
+		if len(resp.Results.MiddleAreas) == 0 || start > resp.Results.ResultsAvailable {
+			return out, nil
+		}
+	}
+}
+
+// SmallAreas pages through the small areas matching p.
+func SmallAreas(c *api.Client, p api.SmallAreaParams) ([]api.SmallArea, error) {
+	var out []api.SmallArea
+	for start := 1; ; {
+		count := areaPageSize
+		p.Start, p.Count = &start, &count
+		var resp api.SmallAreaResponse
+		if err := c.Get("/small_area/v1/", p, &resp); err != nil {
+			return nil, err
+		}
+		out = append(out, resp.Results.SmallAreas...)
+		start += len(resp.Results.SmallAreas)
+		if len(resp.Results.SmallAreas) == 0 || start > resp.Results.ResultsAvailable {
+			return out, nil
+		}
+	}
+}
+
+// apiVersion records v as the snapshot's API version unless one is set.
+func (s *Snapshot) apiVersion(v string) {
+	if s.APIVersion == "" {
+		s.APIVersion = v
+	}
+}
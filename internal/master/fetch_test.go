@@ -0,0 +1,39 @@
+package master
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/apitest"
+)
+
+// newFakeAPI serves one item per master table, plus total middle and small
+// areas in pages smaller than requested, like a capped API.
+func newFakeAPI(t *testing.T, total int) *api.Client {
+	fake := &apitest.Paged{Total: 1, Sizes: map[string]int{"middle_area": total, "small_area": total}, MaxCount: 2}
+	c := api.NewClient("k")
+	c.BaseURL = fake.Start(t)
+	return c
+}
+
+func TestFetch(t *testing.T) {
+	s, err := Fetch(newFakeAPI(t, 5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.FormatVersion != FormatVersion || s.APIVersion != "1.30" || s.FetchedAt.IsZero() {
+		t.Fatalf("unexpected snapshot header %+v", s)
+	}
+	for _, tbl := range s.Tables() {
+		want := 1
+		if tbl.Name == "middle_area" || tbl.Name == "small_area" {
+			want = 5
+		}
+		if tbl.Count != want {
+			t.Fatalf("%s: got %d items, want %d", tbl.Name, tbl.Count, want)
+		}
+	}
+	if s.SmallAreas[4].Code != "A005" {
+		t.Fatalf("expected pages in order, got %+v", s.SmallAreas)
+	}
+}
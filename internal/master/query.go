@@ -0,0 +1,183 @@
+package master
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Get answers a master endpoint request from the snapshot, applying the
+// same code, parent and keyword filters and paging as the API. It accepts
+// the params and response types api.Client.Get would, so commands can use
+// either interchangeably.
+func (s *Snapshot) Get(path string, params any, out any) error {
+	switch path {
+	case "/genre/v1/":
+		p, _ := params.(api.GenreParams)
+		items := filterItems(s.Genres, func(g api.Genre) bool {
+			return inCodes(p.Code, g.Code) && hasKeyword(p.Keyword, g.Name)
+		})
+		r, ok := out.(*api.GenreResponse)
+		if !ok {
+			break
+		}
+		r.Results.Genres = items
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(items), 1)
+		return nil
+
+	case "/budget/v1/":
+		r, ok := out.(*api.BudgetResponse)
+		if !ok {
+			break
+		}
+		r.Results.Budgets = s.Budgets
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(s.Budgets), len(s.Budgets), 1)
+		return nil
+
+	case "/credit_card/v1/":
+		r, ok := out.(*api.CreditCardResponse)
+		if !ok {
+			break
+		}
+		r.Results.CreditCards = s.CreditCards
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(s.CreditCards), len(s.CreditCards), 1)
+		return nil
+
+	case "/special/v1/":
+		p, _ := params.(api.SpecialParams)
+		items := filterItems(s.Specials, func(sp api.Special) bool {
+			return inCodes(p.Special, sp.Code) && inCodes(p.SpecialCategory, sp.SpecialCategory.Code)
+		})
+		r, ok := out.(*api.SpecialResponse)
+		if !ok {
+			break
+		}
+		r.Results.Specials = items
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(items), 1)
+		return nil
+
+	case "/special_category/v1/":
+		p, _ := params.(api.SpecialCategoryParams)
+		items := filterItems(s.SpecialCategories, func(c api.SpecialCategory) bool {
+			return inCodes(p.SpecialCategory, c.Code)
+		})
+		r, ok := out.(*api.SpecialCategoryResponse)
+		if !ok {
+			break
+		}
+		r.Results.SpecialCategories = items
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(items), 1)
+		return nil
+
+	case "/large_service_area/v1/":
+		r, ok := out.(*api.LargeServiceAreaResponse)
+		if !ok {
+			break
+		}
+		r.Results.LargeServiceAreas = s.LargeServiceAreas
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(s.LargeServiceAreas), len(s.LargeServiceAreas), 1)
+		return nil
+
+	case "/service_area/v1/":
+		r, ok := out.(*api.ServiceAreaResponse)
+		if !ok {
+			break
+		}
+		r.Results.ServiceAreas = s.ServiceAreas
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(s.ServiceAreas), len(s.ServiceAreas), 1)
+		return nil
+
+	case "/large_area/v1/":
+		p, _ := params.(api.LargeAreaParams)
+		items := filterItems(s.LargeAreas, func(a api.LargeArea) bool {
+			return inCodes(p.LargeArea, a.Code) && hasKeyword(p.Keyword, a.Name)
+		})
+		r, ok := out.(*api.LargeAreaResponse)
+		if !ok {
+			break
+		}
+		r.Results.LargeAreas = items
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(items), 1)
+		return nil
+
+	case "/middle_area/v1/":
+		p, _ := params.(api.MiddleAreaParams)
+		items := filterItems(s.MiddleAreas, func(a api.MiddleArea) bool {
+			return inCodes(p.MiddleArea, a.Code) && inCodes(p.LargeArea, a.LargeArea.Code) && hasKeyword(p.Keyword, a.Name)
+		})
+		r, ok := out.(*api.MiddleAreaResponse)
+		if !ok {
+			break
+		}
+		start, page := paginate(items, p.Start, p.Count)
+		r.Results.MiddleAreas = page
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(page), start)
+		return nil
+
+	case "/small_area/v1/":
+		p, _ := params.(api.SmallAreaParams)
+		items := filterItems(s.SmallAreas, func(a api.SmallArea) bool {
+			return inCodes(p.SmallArea, a.Code) && inCodes(p.MiddleArea, a.MiddleArea.Code) && hasKeyword(p.Keyword, a.Name)
+		})
+		r, ok := out.(*api.SmallAreaResponse)
+		if !ok {
+			break
+		}
+		start, page := paginate(items, p.Start, p.Count)
+		r.Results.SmallAreas = page
+		s.fill(&r.Results.APIVersion, &r.Results.ResultsAvailable, &r.Results.ResultsReturned, &r.Results.ResultsStart, len(items), len(page), start)
+		return nil
+
+	default:
+		return fmt.Errorf("%s is not available offline", path)
+	}
+	return fmt.Errorf("unexpected response type %T for %s", out, path)
+}
+
+// fill sets the result counters shared by every response type.
+func (s *Snapshot) fill(version *string, available *int, returned *string, start *int, nAvailable, nReturned, first int) {
+	*version = s.APIVersion
+	*available = nAvailable
+	*returned = strconv.Itoa(nReturned)
+	*start = first
+}
+
+func filterItems[T any](items []T, keep func(T) bool) []T {
+	out := []T{}
+	for _, it := range items {
+		if keep(it) {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// paginate returns the page of items selected by 1-based start and count;
+// without count every item from start is returned.
+func paginate[T any](items []T, start, count *int) (int, []T) {
+	from := 1
+	if start != nil && *start > 1 {
+		from = *start
+	}
+	if from > len(items) {
+		return from, []T{}
+	}
+	page := items[from-1:]
+	if count != nil && *count > 0 && *count < len(page) {
+		page = page[:*count]
+	}
+	return from, page
+}
+
+func inCodes(codes []string, code string) bool {
+	return len(codes) == 0 || slices.Contains(codes, code)
+}
+
+// hasKeyword reports whether name contains keyword, ignoring case; a nil
+// keyword matches everything.
+func hasKeyword(keyword *string, name string) bool {
+	return keyword == nil || strings.Contains(strings.ToLower(name), strings.ToLower(*keyword))
+}
@@ -0,0 +1,58 @@
+package master
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func testSnapshot() *Snapshot {
+	tokyo := api.CodeName{Code: "Z011", Name: "東京"}
+	return &Snapshot{
+		APIVersion: "1.30",
+		Genres:     []api.Genre{{Code: "G001", Name: "居酒屋"}, {Code: "G013", Name: "ラーメン"}},
+		MiddleAreas: []api.MiddleArea{
+			{Code: "Y005", Name: "新橋・汐留", LargeArea: tokyo},
+			{Code: "Y030", Name: "浜松町・田町", LargeArea: tokyo},
+			{Code: "Y100", Name: "梅田", LargeArea: api.CodeName{Code: "Z023", Name: "大阪"}},
+		},
+	}
+}
+
+func TestSnapshotGet(t *testing.T) {
+	s := testSnapshot()
+
+	var genres api.GenreResponse
+	keyword := "ラーメン"
+	if err := s.Get("/genre/v1/", api.GenreParams{Keyword: &keyword}, &genres); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(genres.Results.Genres) != 1 || genres.Results.Genres[0].Code != "G013" || genres.Results.APIVersion != "1.30" {
+		t.Fatalf("unexpected genres %+v", genres.Results)
+	}
+
+	var middle api.MiddleAreaResponse
+	start, count := 2, 5
+	if err := s.Get("/middle_area/v1/", api.MiddleAreaParams{LargeArea: []string{"Z011"}, Start: &start, Count: &count}, &middle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := middle.Results
+	if r.ResultsAvailable != 2 || r.ResultsReturned != "1" || r.ResultsStart != 2 || r.MiddleAreas[0].Code != "Y030" {
+		t.Fatalf("unexpected middle areas %+v", r)
+	}
+
+	var all api.MiddleAreaResponse
+	if err := s.Get("/middle_area/v1/", nil, &all); err != nil || len(all.Results.MiddleAreas) != 3 {
+		t.Fatalf("expected every middle area without params, got %+v, %v", all.Results, err)
+	}
+}
+
+func TestSnapshotGet_Errors(t *testing.T) {
+	s := testSnapshot()
+	if err := s.Get("/gourmet/v1/", nil, &api.GourmetResponse{}); err == nil {
+		t.Fatalf("expected gourmet search to be unavailable offline")
+	}
+	if err := s.Get("/genre/v1/", nil, &api.BudgetResponse{}); err == nil {
+		t.Fatalf("expected mismatched response type to fail")
+	}
+}
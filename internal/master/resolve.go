@@ -0,0 +1,114 @@
+package master
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// codeRe matches master codes such as G001, B003, SA11, Z011, Y005 or c01.
+var codeRe = regexp.MustCompile(`^[A-Za-z]{1,3}\d+$`)
+
+// IsCode reports whether v looks like a master code rather than a name.
+func IsCode(v string) bool {
+	return codeRe.MatchString(v)
+}
+
+// Entries returns the codes and names of a table, named as in Tables.
+func (s *Snapshot) Entries(table string) []api.CodeName {
+	var out []api.CodeName
+	add := func(code, name string) { out = append(out, api.CodeName{Code: code, Name: name}) }
+	switch table {
+	case "genre":
+		for _, e := range s.Genres {
+			add(e.Code, e.Name)
+		}
+	case "budget":
+		for _, e := range s.Budgets {
+			add(e.Code, e.Name)
+		}
+	case "credit_card":
+		for _, e := range s.CreditCards {
+			add(e.Code, e.Name)
+		}
+	case "special":
+		for _, e := range s.Specials {
+			add(e.Code, e.Name)
+		}
+	case "special_category":
+		for _, e := range s.SpecialCategories {
+			add(e.Code, e.Name)
+		}
+	case "large_service_area":
+		for _, e := range s.LargeServiceAreas {
+			add(e.Code, e.Name)
+		}
+	case "service_area":
+		for _, e := range s.ServiceAreas {
+			add(e.Code, e.Name)
+		}
+	case "large_area":
+		for _, e := range s.LargeAreas {
+			add(e.Code, e.Name)
+		}
+	case "middle_area":
+		for _, e := range s.MiddleAreas {
+			add(e.Code, e.Name)
+		}
+	case "small_area":
+		for _, e := range s.SmallAreas {
+			add(e.Code, e.Name)
+		}
+	}
+	return out
+}
+
+// Resolve returns the code of table named by v. Codes are returned as is;
+// names match exactly (ignoring case) or, failing that, as a unique
+// substring, so "ラーメン" and "浜松町" both resolve.
+func (s *Snapshot) Resolve(table, v string) (string, error) {
+	entries := s.Entries(table)
+	var partial []api.CodeName
+	for _, e := range entries {
+		if e.Code == v || strings.EqualFold(e.Name, v) {
+			return e.Code, nil
+		}
+		if strings.Contains(strings.ToLower(e.Name), strings.ToLower(v)) {
+			partial = append(partial, e)
+		}
+	}
+	switch len(partial) {
+	case 1:
+		return partial[0].Code, nil
+	case 0:
+		return "", fmt.Errorf("unknown %s %q", strings.ReplaceAll(table, "_", " "), v)
+	}
+	var names []string
+	for _, e := range partial[:min(len(partial), 5)] {
+		names = append(names, e.Code+" "+e.Name)
+	}
+	more := ""
+	if len(partial) > 5 {
+		more = fmt.Sprintf(", and %d more", len(partial)-5)
+	}
+	return "", fmt.Errorf("ambiguous %s %q: %s%s", strings.ReplaceAll(table, "_", " "), v, strings.Join(names, ", "), more)
+}
+
+var codeTokenRe = regexp.MustCompile(`\b[A-Za-z]{1,3}\d+\b`)
+
+// ReferencedCodes returns the distinct tokens in data that look like master
+// codes, in order of first appearance. It works on any text format, such as
+// saved flags or JSON query files.
+func ReferencedCodes(data []byte) []string {
+	seen := map[string]bool{}
+	var codes []string
+	for _, m := range codeTokenRe.FindAll(data, -1) {
+		if c := string(m); !seen[c] {
+			seen[c] = true
+			codes = append(codes, c)
+		}
+	}
+	return codes
+}
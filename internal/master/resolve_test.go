@@ -0,0 +1,54 @@
+package master
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	s := testSnapshot()
+	tests := []struct {
+		table, v, want, err string
+	}{
+		{"genre", "G001", "G001", ""},
+		{"genre", "居酒屋", "G001", ""},
+		{"genre", "ラー", "G013", ""},
+		{"middle_area", "浜松町", "Y030", ""},
+		{"middle_area", "・", "", "ambiguous middle area"},
+		{"genre", "寿司", "", "unknown genre"},
+	}
+	for _, tt := range tests {
+		got, err := s.Resolve(tt.table, tt.v)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("%s %s: expected error %q, got %v", tt.table, tt.v, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("%s %s: got %q, %v; want %q", tt.table, tt.v, got, err, tt.want)
+		}
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	for _, v := range []string{"G001", "SA11", "Z011", "c01", "LT0050"} {
+		if !IsCode(v) {
+			t.Fatalf("expected %s to be a code", v)
+		}
+	}
+	for _, v := range []string{"居酒屋", "ramen", "G001a"} {
+		if IsCode(v) {
+			t.Fatalf("expected %s to be a name", v)
+		}
+	}
+}
+
+func TestReferencedCodes(t *testing.T) {
+	data := []byte(`{"label":"新橋","genre":["G001","G013"],"middle_area":["Y005"]}
+--genre G001 --budget B003 --keyword izakayaG002`)
+	got := strings.Join(ReferencedCodes(data), ",")
+	if got != "G001,G013,Y005,B003" {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -0,0 +1,51 @@
+package master
+
+import (
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// FormatVersion is the version of the snapshot file layout. Snapshots with a
+// different version are rejected when loaded.
+const FormatVersion = 1
+
+// Snapshot is a local copy of every master table.
+type Snapshot struct {
+	FormatVersion int       `json:"format_version"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	APIVersion    string    `json:"api_version"`
+
+	Genres            []api.Genre            `json:"genre"`
+	Budgets           []api.BudgetMaster     `json:"budget"`
+	CreditCards       []api.CreditCard       `json:"credit_card"`
+	Specials          []api.Special          `json:"special"`
+	SpecialCategories []api.SpecialCategory  `json:"special_category"`
+	LargeServiceAreas []api.LargeServiceArea `json:"large_service_area"`
+	ServiceAreas      []api.ServiceArea      `json:"service_area"`
+	LargeAreas        []api.LargeArea        `json:"large_area"`
+	MiddleAreas       []api.MiddleArea       `json:"middle_area"`
+	SmallAreas        []api.SmallArea        `json:"small_area"`
+}
+
+// Table is the name and size of one master table in a snapshot.
+type Table struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Tables lists the tables of s in fetch order.
+func (s *Snapshot) Tables() []Table {
+	return []Table{
+		{"genre", len(s.Genres)},
+		{"budget", len(s.Budgets)},
+		{"credit_card", len(s.CreditCards)},
+		{"special", len(s.Specials)},
+		{"special_category", len(s.SpecialCategories)},
+		{"large_service_area", len(s.LargeServiceAreas)},
+		{"service_area", len(s.ServiceAreas)},
+		{"large_area", len(s.LargeAreas)},
+		{"middle_area", len(s.MiddleAreas)},
+		{"small_area", len(s.SmallAreas)},
+	}
+}
@@ -0,0 +1,119 @@
+package master
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNoSnapshot is returned by Store.Latest when nothing has been synced.
+var ErrNoSnapshot = errors.New("no master snapshot found; run `hpp sync` first")
+
+const (
+	filePrefix = "master-"
+	fileSuffix = ".json"
+	timeLayout = "20060102T150405Z"
+)
+
+// Store keeps snapshots as timestamped JSON files in Dir, so older versions
+// remain available for comparison.
+type Store struct {
+	Dir string
+}
+
+// DefaultStore returns the store in the user's cache directory
+// (e.g. ~/.cache/hpp/master).
+func DefaultStore() (*Store, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("locating cache directory: %w", err)
+	}
+	return &Store{Dir: filepath.Join(dir, "hpp", "master")}, nil
+}
+
+// Save writes s to a new file named after its fetch time and returns the
+// file's path.
+func (st *Store) Save(s *Snapshot) (string, error) {
+	if err := os.MkdirAll(st.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encoding snapshot: %w", err)
+	}
+	path := filepath.Join(st.Dir, filePrefix+s.FetchedAt.UTC().Format(timeLayout)+fileSuffix)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// List returns the paths of all snapshots, oldest first.
+func (st *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(st.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			paths = append(paths, filepath.Join(st.Dir, name))
+		}
+	}
+	slices.Sort(paths) // timestamps sort chronologically
+	return paths, nil
+}
+
+// Latest loads the most recent snapshot.
+func (st *Store) Latest() (*Snapshot, error) {
+	paths, err := st.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	return Load(paths[len(paths)-1])
+}
+
+// Prune removes all but the keep most recent snapshots.
+func (st *Store) Prune(keep int) error {
+	paths, err := st.List()
+	if err != nil {
+		return err
+	}
+	for len(paths) > max(keep, 1) {
+		if err := os.Remove(paths[0]); err != nil {
+			return fmt.Errorf("removing old snapshot: %w", err)
+		}
+		paths = paths[1:]
+	}
+	return nil
+}
+
+// Load reads a snapshot file.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decoding snapshot %s: %w", path, err)
+	}
+	if s.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("snapshot %s has format version %d, want %d; run `hpp sync` again", path, s.FormatVersion, FormatVersion)
+	}
+	return &s, nil
+}
@@ -0,0 +1,51 @@
+package master
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestStore(t *testing.T) {
+	st := &Store{Dir: filepath.Join(t.TempDir(), "master")}
+	if _, err := st.Latest(); !errors.Is(err, ErrNoSnapshot) {
+		t.Fatalf("expected ErrNoSnapshot, got %v", err)
+	}
+
+	base := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"居酒屋", "居酒屋・バー", "居酒屋・ダイニング"} {
+		s := &Snapshot{FormatVersion: FormatVersion, FetchedAt: base.Add(time.Duration(i) * time.Hour), Genres: []api.Genre{{Code: "G001", Name: name}}}
+		if _, err := st.Save(s); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	latest, err := st.Latest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest.Genres[0].Name != "居酒屋・ダイニング" || !latest.FetchedAt.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("expected the newest snapshot, got %+v", latest)
+	}
+
+	if err := st.Prune(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	paths, _ := st.List()
+	if len(paths) != 2 || filepath.Base(paths[0]) != "master-20260401T100000Z.json" {
+		t.Fatalf("expected the two newest snapshots to remain, got %v", paths)
+	}
+}
+
+func TestLoad_FormatVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.json")
+	if err := os.WriteFile(path, []byte(`{"format_version":0}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected an error for an old format")
+	}
+}
@@ -0,0 +1,100 @@
+package master
+
+import "strings"
+
+// AreaLevels names the area hierarchy levels, broadest first.
+var AreaLevels = []string{"large_service_area", "service_area", "large_area", "middle_area", "small_area"}
+
+// Node is an area in the hierarchy with its sub-areas.
+type Node struct {
+	Level    string  `json:"level"`
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Counts   Counts  `json:"counts,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Counts maps each level below a node to its number of areas there.
+type Counts map[string]int
+
+// AreaTree assembles the area tables into a forest rooted at the large
+// service areas. Areas whose parent is missing from the snapshot are left
+// out.
+func (s *Snapshot) AreaTree() []*Node {
+	byCode := map[string]*Node{}
+	var roots []*Node
+	add := func(level, code, name, parent string) {
+		n := &Node{Level: level, Code: code, Name: name}
+		byCode[level+"/"+code] = n
+		if parent == "" {
+			roots = append(roots, n)
+			return
+		}
+		if p, ok := byCode[parent]; ok {
+			p.Children = append(p.Children, n)
+		}
+	}
+	for _, a := range s.LargeServiceAreas {
+		add("large_service_area", a.Code, a.Name, "")
+	}
+	for _, a := range s.ServiceAreas {
+		add("service_area", a.Code, a.Name, "large_service_area/"+a.LargeServiceArea.Code)
+	}
+	for _, a := range s.LargeAreas {
+		add("large_area", a.Code, a.Name, "service_area/"+a.ServiceArea.Code)
+	}
+	for _, a := range s.MiddleAreas {
+		add("middle_area", a.Code, a.Name, "large_area/"+a.LargeArea.Code)
+	}
+	for _, a := range s.SmallAreas {
+		add("small_area", a.Code, a.Name, "middle_area/"+a.MiddleArea.Code)
+	}
+	for _, r := range roots {
+		r.count()
+	}
+	return roots
+}
+
+// count fills Counts for n and its descendants.
+func (n *Node) count() Counts {
+	if len(n.Children) == 0 {
+		return nil
+	}
+	n.Counts = Counts{}
+	for _, c := range n.Children {
+		n.Counts[c.Level]++
+		for level, k := range c.count() {
+			n.Counts[level] += k
+		}
+	}
+	return n.Counts
+}
+
+// FindNode returns the first node, depth-first, whose code equals v or
+// whose name equals v ignoring case.
+func FindNode(roots []*Node, v string) *Node {
+	for _, n := range roots {
+		if n.Code == v || strings.EqualFold(n.Name, v) {
+			return n
+		}
+		if found := FindNode(n.Children, v); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Truncate drops descendants more than depth levels below n (counting n
+// as level 1); Counts still cover the full hierarchy. Depth 0 keeps all.
+func (n *Node) Truncate(depth int) {
+	if depth <= 0 {
+		return
+	}
+	if depth == 1 {
+		n.Children = nil
+		return
+	}
+	for _, c := range n.Children {
+		c.Truncate(depth - 1)
+	}
+}
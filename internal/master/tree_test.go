@@ -0,0 +1,62 @@
+package master
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func treeSnapshot() *Snapshot {
+	kanto := api.CodeName{Code: "SS10", Name: "関東"}
+	tokyoSA := api.CodeName{Code: "SA11", Name: "東京"}
+	tokyo := api.CodeName{Code: "Z011", Name: "東京"}
+	shimbashi := api.CodeName{Code: "Y005", Name: "新橋・汐留"}
+	hamamatsucho := api.CodeName{Code: "Y030", Name: "浜松町・田町"}
+	return &Snapshot{
+		LargeServiceAreas: []api.LargeServiceArea{{Code: "SS10", Name: "関東"}},
+		ServiceAreas:      []api.ServiceArea{{Code: "SA11", Name: "東京", LargeServiceArea: kanto}},
+		LargeAreas:        []api.LargeArea{{Code: "Z011", Name: "東京", ServiceArea: tokyoSA}},
+		MiddleAreas: []api.MiddleArea{
+			{Code: "Y005", Name: "新橋・汐留", LargeArea: tokyo},
+			{Code: "Y030", Name: "浜松町・田町", LargeArea: tokyo},
+		},
+		SmallAreas: []api.SmallArea{
+			{Code: "X001", Name: "新橋駅前", MiddleArea: shimbashi},
+			{Code: "X002", Name: "汐留", MiddleArea: shimbashi},
+			{Code: "X085", Name: "浜松町", MiddleArea: hamamatsucho},
+			{Code: "X999", Name: "迷子", MiddleArea: api.CodeName{Code: "Y999"}},
+		},
+	}
+}
+
+func TestAreaTree(t *testing.T) {
+	roots := treeSnapshot().AreaTree()
+	if len(roots) != 1 || roots[0].Code != "SS10" {
+		t.Fatalf("unexpected roots %+v", roots)
+	}
+	want := Counts{"service_area": 1, "large_area": 1, "middle_area": 2, "small_area": 3}
+	for level, n := range want {
+		if roots[0].Counts[level] != n {
+			t.Fatalf("%s: got %d, want %d (counts %v)", level, roots[0].Counts[level], n, roots[0].Counts)
+		}
+	}
+
+	z011 := FindNode(roots, "Z011")
+	if z011 == nil || z011.Level != "large_area" || len(z011.Children) != 2 {
+		t.Fatalf("unexpected Z011 node %+v", z011)
+	}
+	if n := FindNode(roots, "浜松町・田町"); n == nil || n.Code != "Y030" || n.Counts["small_area"] != 1 {
+		t.Fatalf("expected lookup by name, got %+v", n)
+	}
+	if FindNode(roots, "X999") != nil {
+		t.Fatalf("expected orphaned area to be left out")
+	}
+
+	z011.Truncate(2)
+	if len(z011.Children) != 2 || z011.Children[0].Children != nil {
+		t.Fatalf("expected truncation below middle areas, got %+v", z011.Children[0])
+	}
+	if z011.Counts["small_area"] != 3 {
+		t.Fatalf("expected counts to survive truncation, got %v", z011.Counts)
+	}
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaFor derives a JSON Schema object for the struct v from its json
+// tags (falling back to url tags). describe, if non-nil, supplies a
+// description for each property by its JSON name.
+func SchemaFor(v any, describe func(name string) string) map[string]any {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	props := map[string]any{}
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, omitempty := fieldName(f)
+		if name == "" {
+			continue
+		}
+		prop := typeSchema(f.Type)
+		if prop == nil {
+			continue
+		}
+		if describe != nil {
+			if d := describe(name); d != "" {
+				prop["description"] = d
+			}
+		}
+		props[name] = prop
+		if !omitempty && f.Type.Kind() != reflect.Pointer && f.Type.Kind() != reflect.Bool {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldName(f reflect.StructField) (name string, omitempty bool) {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		tag, ok = f.Tag.Lookup("url")
+	}
+	if !ok {
+		return f.Name, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,")
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		items := typeSchema(t.Elem())
+		if items == nil {
+			return nil
+		}
+		return map[string]any{"type": "array", "items": items}
+	}
+	return nil
+}
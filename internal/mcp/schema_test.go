@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestSchemaFor_Params(t *testing.T) {
+	s := SchemaFor(api.GourmetSearchParams{}, func(name string) string {
+		if name == "keyword" {
+			return "Free keyword"
+		}
+		return ""
+	})
+	if s["type"] != "object" || s["additionalProperties"] != false {
+		t.Fatalf("unexpected schema header %v", s)
+	}
+	if _, ok := s["required"]; ok {
+		t.Fatalf("expected no required fields, got %v", s["required"])
+	}
+	props := s["properties"].(map[string]any)
+	tests := map[string]map[string]any{
+		"keyword":     {"type": "string", "description": "Free keyword"},
+		"lat":         {"type": "number"},
+		"range":       {"type": "integer"},
+		"wifi":        {"type": "boolean"},
+		"genre":       {"type": "array", "items": map[string]any{"type": "string"}},
+		"ktai_coupon": {"type": "integer"},
+	}
+	for name, want := range tests {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+	}
+	if len(props) != reflect.TypeOf(api.GourmetSearchParams{}).NumField() {
+		t.Fatalf("expected one property per field, got %d", len(props))
+	}
+}
+
+func TestSchemaFor_Required(t *testing.T) {
+	type args struct {
+		ID    []string `json:"id"`
+		Count *int     `json:"count"`
+		Skip  string   `json:"-"`
+	}
+	s := SchemaFor(args{}, nil)
+	if got := s["required"]; !reflect.DeepEqual(got, []string{"id"}) {
+		t.Fatalf("expected id required, got %v", got)
+	}
+	if _, ok := s["properties"].(map[string]any)["Skip"]; ok {
+		t.Fatalf("expected json:\"-\" field to be skipped")
+	}
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// LatestProtocolVersion is the newest MCP revision this server speaks.
+const LatestProtocolVersion = "2025-06-18"
+
+var supportedVersions = []string{"2024-11-05", "2025-03-26", LatestProtocolVersion}
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Handler runs a tool with its raw JSON arguments and returns a value to be
+// sent back as JSON. Errors are reported to the client as tool errors.
+type Handler func(args json.RawMessage) (any, error)
+
+// Tool is a callable tool exposed by the server.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	Handler     Handler        `json:"-"`
+}
+
+// Server is a Model Context Protocol server exposing tools over a
+// newline-delimited JSON-RPC stream such as stdio.
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version}
+}
+
+// AddTool registers a tool. Tools are listed in registration order.
+func (s *Server) AddTool(t Tool) {
+	s.tools = append(s.tools, t)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r and writes responses to w until r is
+// exhausted. Requests are handled one at a time, in order.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(w)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handle(line); resp != nil {
+			if err := enc.Encode(resp); err != nil {
+				return fmt.Errorf("writing response: %w", err)
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// handle processes one message and returns the response, or nil for
+// notifications.
+func (s *Server) handle(msg []byte) *response {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "parse error: "+err.Error())
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(idOrNull(req.ID), codeInvalidRequest, "invalid request")
+	}
+	// Notifications (no id) never get a response.
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	var (
+		result any
+		rerr   *rpcError
+	)
+	switch req.Method {
+	case "initialize":
+		result = s.initialize(req.Params)
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result = map[string]any{"tools": s.tools}
+	case "tools/call":
+		result, rerr = s.callTool(req.Params)
+	default:
+		rerr = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	if rerr != nil {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) initialize(params json.RawMessage) any {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(params, &p)
+	version := LatestProtocolVersion
+	if slices.Contains(supportedVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]any{"name": s.name, "version": s.version},
+	}
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content           []content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+func (s *Server) callTool(params json.RawMessage) (any, *rpcError) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	i := slices.IndexFunc(s.tools, func(t Tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + p.Name}
+	}
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	out, err := s.tools[i].Handler(args)
+	if err != nil {
+		return callResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, err := json.Marshal(out)
+	if err != nil {
+		return callResult{Content: []content{{Type: "text", Text: "encoding result: " + err.Error()}}, IsError: true}, nil
+	}
+	return callResult{Content: []content{{Type: "text", Text: string(text)}}, StructuredContent: out}, nil
+}
+
+// DecodeArgs strictly decodes tool arguments into v, rejecting unknown
+// fields so that misspelled parameters are reported instead of ignored.
+func DecodeArgs(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *response {
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: msg}}
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
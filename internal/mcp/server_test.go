@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *Server {
+	s := NewServer("hpp", "test")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the message",
+		InputSchema: SchemaFor(struct {
+			Msg string `json:"msg"`
+		}{}, nil),
+		Handler: func(args json.RawMessage) (any, error) {
+			var a struct {
+				Msg string `json:"msg"`
+			}
+			if err := DecodeArgs(args, &a); err != nil {
+				return nil, err
+			}
+			if a.Msg == "" {
+				return nil, errors.New("msg is required")
+			}
+			return map[string]string{"msg": a.Msg}, nil
+		},
+	})
+	return s
+}
+
+// roundTrip feeds input lines to a server and decodes every response line.
+func roundTrip(t *testing.T, s *Server, lines ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := s.Serve(strings.NewReader(strings.Join(lines, "\n")), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resps []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		resps = append(resps, m)
+	}
+	return resps
+}
+
+func TestServe_Handshake(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"c","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	)
+	if len(resps) != 3 {
+		t.Fatalf("expected 3 responses (none for the notification), got %d", len(resps))
+	}
+	init := resps[0]["result"].(map[string]any)
+	if init["protocolVersion"] != "2024-11-05" {
+		t.Fatalf("expected requested version to be echoed, got %v", init["protocolVersion"])
+	}
+	if init["serverInfo"].(map[string]any)["name"] != "hpp" {
+		t.Fatalf("unexpected serverInfo %v", init["serverInfo"])
+	}
+	tools := resps[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "echo" {
+		t.Fatalf("unexpected tools %v", tools)
+	}
+	if _, ok := tools[0].(map[string]any)["inputSchema"]; !ok {
+		t.Fatalf("expected inputSchema in tool listing")
+	}
+	if resps[2]["id"] != float64(3) || resps[2]["error"] != nil {
+		t.Fatalf("unexpected ping response %v", resps[2])
+	}
+}
+
+func TestServe_UnsupportedVersion(t *testing.T) {
+	resps := roundTrip(t, newTestServer(), `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+	if got := resps[0]["result"].(map[string]any)["protocolVersion"]; got != LatestProtocolVersion {
+		t.Fatalf("expected latest version, got %v", got)
+	}
+}
+
+func TestServe_ToolCall(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"msg":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"mgs":"typo"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	ok := resps[0]["result"].(map[string]any)
+	if ok["isError"] != nil {
+		t.Fatalf("expected success, got %v", ok)
+	}
+	if text := ok["content"].([]any)[0].(map[string]any)["text"]; text != `{"msg":"hi"}` {
+		t.Fatalf("unexpected text content %v", text)
+	}
+	if ok["structuredContent"].(map[string]any)["msg"] != "hi" {
+		t.Fatalf("unexpected structured content %v", ok["structuredContent"])
+	}
+
+	for _, i := range []int{1, 2} {
+		res := resps[i]["result"].(map[string]any)
+		if res["isError"] != true {
+			t.Fatalf("response %d: expected tool error, got %v", i, res)
+		}
+	}
+	if !strings.Contains(resps[2]["result"].(map[string]any)["content"].([]any)[0].(map[string]any)["text"].(string), "mgs") {
+		t.Fatalf("expected unknown argument to be named, got %v", resps[2])
+	}
+
+	if code := resps[3]["error"].(map[string]any)["code"]; code != float64(codeInvalidParams) {
+		t.Fatalf("expected invalid params for unknown tool, got %v", resps[3])
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	resps := roundTrip(t, newTestServer(),
+		`not json`,
+		`{"jsonrpc":"2.0","id":"a","method":"resources/list"}`,
+	)
+	if code := resps[0]["error"].(map[string]any)["code"]; code != float64(codeParseError) {
+		t.Fatalf("expected parse error, got %v", resps[0])
+	}
+	if resps[1]["id"] != "a" || resps[1]["error"].(map[string]any)["code"] != float64(codeMethodNotFound) {
+		t.Fatalf("expected method not found, got %v", resps[1])
+	}
+}
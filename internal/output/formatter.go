@@ -11,8 +11,13 @@ type TableWriter struct {
 	w *tabwriter.Writer
 }
 
+// NewTableWriter starts a table on out. With no headers, no header line is
+// written.
 func NewTableWriter(out io.Writer, headers []string) *TableWriter {
 	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(headers) == 0 {
+		return &TableWriter{w: w}
+	}
 	for i, h := range headers {
 		if i > 0 {
 			_, _ = fmt.Fprint(w, "\t")
@@ -22,6 +22,16 @@ func TestTableWriter(t *testing.T) {
 	}
 }
 
+func TestTableWriter_NoHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewTableWriter(&buf, nil)
+	tw.Row("Name:", "Sushi Place")
+	tw.Flush()
+	if got := buf.String(); got != "Name:  Sushi Place\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
 func TestJSONOutput(t *testing.T) {
 	var buf bytes.Buffer
 	data := map[string]string{"name": "test"}
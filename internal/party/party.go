@@ -0,0 +1,172 @@
+package party
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Criterion is one requirement for a party venue.
+type Criterion string
+
+const (
+	Capacity    Criterion = "party_capacity" // seats the whole group
+	PrivateRoom Criterion = "private_room"
+	Charter     Criterion = "charter" // can be booked out
+	Course      Criterion = "course"
+	FreeDrink   Criterion = "free_drink"
+	Budget      Criterion = "budget" // average spend within the per-person budget
+)
+
+// Criteria lists every criterion in the order they are explained.
+var Criteria = []Criterion{Capacity, PrivateRoom, Charter, Course, FreeDrink, Budget}
+
+// Tiers lists the criteria to search with, strictest first. Each tier
+// drops the criterion a large group can most easily do without.
+var Tiers = [][]Criterion{
+	{Capacity, PrivateRoom, Charter, Course, FreeDrink, Budget},
+	{Capacity, PrivateRoom, Course, FreeDrink, Budget},
+	{Capacity, Course, FreeDrink, Budget},
+	{Capacity, FreeDrink, Budget},
+	{Capacity, Budget},
+	{Capacity},
+}
+
+// Request is what a group needs: its size and, when set, the most it
+// spends per person in yen.
+type Request struct {
+	People    int
+	BudgetYen int
+}
+
+// Tiers returns the tiers that apply to r: without a budget, tiers that
+// only differ by the budget are merged.
+func (r Request) Tiers() [][]Criterion {
+	var tiers [][]Criterion
+	for _, t := range Tiers {
+		if r.BudgetYen == 0 {
+			t = without(t, Budget)
+			if len(tiers) > 0 && len(tiers[len(tiers)-1]) == len(t) {
+				continue
+			}
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers
+}
+
+func without(cs []Criterion, drop Criterion) []Criterion {
+	var out []Criterion
+	for _, c := range cs {
+		if c != drop {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Params adds the API filters for a tier to p. The budget is checked on
+// fetched shops with Met instead, since the API matches budget bands.
+func (r Request) Params(p api.GourmetSearchParams, tier []Criterion) (api.GourmetSearchParams, error) {
+	for _, c := range tier {
+		switch c {
+		case Capacity:
+			n := r.People
+			p.PartyCapacity = &n
+		case Budget:
+		default:
+			if !p.SetAmenity(string(c)) {
+				return p, fmt.Errorf("unknown party criterion %q", c)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Check is whether a shop meets one criterion, and the shop's value.
+type Check struct {
+	Criterion Criterion `json:"criterion"`
+	Met       bool      `json:"met"`
+	Detail    string    `json:"detail"`
+}
+
+// Met reports whether s meets c for the group.
+func (r Request) Met(s *api.Shop, c Criterion) Check {
+	switch c {
+	case Capacity:
+		seats := int(s.PartyCapacity)
+		return Check{c, seats >= r.People, fmt.Sprintf("party %d", seats)}
+	case Budget:
+		yen := s.Budget.AverageYen()
+		if yen == 0 {
+			return Check{c, false, "budget unknown"}
+		}
+		return Check{c, yen <= r.BudgetYen, fmt.Sprintf("%d円", yen)}
+	default:
+		for _, a := range api.Amenities {
+			if a.Name == string(c) {
+				v := a.Shop(s)
+				return Check{c, api.HasAmenity(v), v}
+			}
+		}
+		return Check{c, false, ""}
+	}
+}
+
+// Rationale checks s against every criterion that applies to r.
+func (r Request) Rationale(s *api.Shop) []Check {
+	var checks []Check
+	for _, c := range Criteria {
+		if c == Budget && r.BudgetYen == 0 {
+			continue
+		}
+		checks = append(checks, r.Met(s, c))
+	}
+	return checks
+}
+
+// Labels are the short names of the criteria used in explanations.
+var Labels = map[Criterion]string{
+	Capacity:    "capacity",
+	PrivateRoom: "private room",
+	Charter:     "charter",
+	Course:      "course",
+	FreeDrink:   "free drink",
+	Budget:      "budget",
+}
+
+// Explain summarizes checks on one line, e.g. "✓ party 50, ✓ private
+// room, ✗ charter, ✓ 4500円".
+func Explain(checks []Check) string {
+	parts := make([]string, len(checks))
+	for i, c := range checks {
+		mark := "✗"
+		if c.Met {
+			mark = "✓"
+		}
+		label := Labels[c.Criterion]
+		if c.Criterion == Capacity || c.Criterion == Budget {
+			label = c.Detail
+		}
+		parts[i] = mark + " " + label
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Describe names a tier's criteria, e.g. "capacity 30+, course, free
+// drink, ≤5000円".
+func (r Request) Describe(tier []Criterion) string {
+	parts := make([]string, len(tier))
+	for i, c := range tier {
+		switch c {
+		case Capacity:
+			parts[i] = fmt.Sprintf("capacity %d+", r.People)
+		case Budget:
+			parts[i] = fmt.Sprintf("≤%d円", r.BudgetYen)
+		default:
+			parts[i] = Labels[c]
+		}
+	}
+	return strings.Join(parts, ", ")
+}
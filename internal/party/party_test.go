@@ -0,0 +1,81 @@
+package party
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestTiers(t *testing.T) {
+	r := Request{People: 30, BudgetYen: 5000}
+	if got := r.Tiers(); len(got) != len(Tiers) || got[0][len(got[0])-1] != Budget {
+		t.Fatalf("expected every tier with a budget, got %v", got)
+	}
+	// Without a budget, the last two tiers become the same and merge.
+	r.BudgetYen = 0
+	got := r.Tiers()
+	if len(got) != len(Tiers)-1 {
+		t.Fatalf("expected %d tiers, got %v", len(Tiers)-1, got)
+	}
+	for _, tier := range got {
+		for _, c := range tier {
+			if c == Budget {
+				t.Fatalf("unexpected budget criterion in %v", got)
+			}
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	r := Request{People: 30, BudgetYen: 5000}
+	p, err := r.Params(api.GourmetSearchParams{}, Tiers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PartyCapacity == nil || *p.PartyCapacity != 30 || !p.PrivateRoom || !p.Charter || !p.Course || !p.FreeDrink {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	p, err = r.Params(api.GourmetSearchParams{}, Tiers[len(Tiers)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PrivateRoom || p.FreeDrink || p.PartyCapacity == nil {
+		t.Fatalf("expected only party capacity, got %+v", p)
+	}
+	if _, err := r.Params(api.GourmetSearchParams{}, []Criterion{Capacity, "jacuzzi"}); err == nil {
+		t.Fatal("expected an error for an unknown criterion")
+	}
+}
+
+func TestRationale(t *testing.T) {
+	r := Request{People: 30, BudgetYen: 5000}
+	s := api.Shop{PartyCapacity: 50, PrivateRoom: "あり ：完全個室", Charter: "貸切不可", Course: "あり", FreeDrink: "あり",
+		Budget: api.Budget{Average: "4500円"}}
+	checks := r.Rationale(&s)
+	if len(checks) != len(Criteria) {
+		t.Fatalf("expected a check per criterion, got %+v", checks)
+	}
+	want := "✓ party 50, ✓ private room, ✗ charter, ✓ course, ✓ free drink, ✓ 4500円"
+	if got := Explain(checks); got != want {
+		t.Fatalf("Explain = %q, want %q", got, want)
+	}
+
+	s.PartyCapacity = 20
+	s.Budget = api.Budget{}
+	if c := r.Met(&s, Capacity); c.Met {
+		t.Fatalf("expected 20 seats to be too few: %+v", c)
+	}
+	if c := r.Met(&s, Budget); c.Met || c.Detail != "budget unknown" {
+		t.Fatalf("unexpected budget check %+v", c)
+	}
+	if got := len(Request{People: 30}.Rationale(&s)); got != len(Criteria)-1 {
+		t.Fatalf("expected no budget check without a budget, got %d checks", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	r := Request{People: 30, BudgetYen: 5000}
+	if got := r.Describe(Tiers[2]); got != "capacity 30+, course, free drink, ≤5000円" {
+		t.Fatalf("Describe = %q", got)
+	}
+}
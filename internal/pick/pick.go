@@ -0,0 +1,154 @@
+package pick
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/rank"
+)
+
+// Factors besides the amenity names that --weight accepts.
+const (
+	Distance = "distance" // closer to the search point
+	Budget   = "budget"   // lower average spend
+)
+
+// ParseWeights builds a rank profile from weights given as
+// "factor=weight" or just "factor" (weight 1), where factor is distance,
+// budget or an amenity name such as private_room. Its limits are left for
+// SetLimits.
+func ParseWeights(specs []string) (rank.Profile, error) {
+	var p rank.Profile
+	for _, spec := range specs {
+		name, val, hasVal := strings.Cut(strings.TrimSpace(spec), "=")
+		if name != Distance && name != Budget && !isAmenity(name) {
+			return rank.Profile{}, fmt.Errorf("unknown factor %q (want distance, budget or an amenity such as private_room)", name)
+		}
+		weight := 1.0
+		if hasVal {
+			var err error
+			weight, err = strconv.ParseFloat(val, 64)
+			if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+				return rank.Profile{}, fmt.Errorf("%s: weight must be a finite number >= 0, got %q", name, val)
+			}
+		}
+		switch name {
+		case Distance:
+			p.Distance = weight
+		case Budget:
+			p.Budget = weight
+		default:
+			if p.Amenities == nil {
+				p.Amenities = map[string]float64{}
+			}
+			p.Amenities[name] = weight
+		}
+	}
+	return p, nil
+}
+
+func isAmenity(name string) bool {
+	return slices.ContainsFunc(api.Amenities, func(a api.Amenity) bool { return a.Name == name })
+}
+
+// Candidate is a shop with its weight in the draw.
+type Candidate struct {
+	Shop    api.Shop         `json:"shop"`
+	Weight  float64          `json:"weight"`
+	Chance  float64          `json:"chance"` // probability of being drawn, set by Choose
+	Factors []rank.Component `json:"factors,omitempty"`
+}
+
+// SetLimits sets the limits p leaves unset from the shops: max_distance
+// is the farthest shop from origin, and budget_yen the midpoint of the
+// cheapest and priciest known average budgets, so that budget fit falls
+// across the candidates' price range instead of only near the cheapest.
+func SetLimits(p *rank.Profile, shops []api.Shop, origin *geo.Point) {
+	if p.MaxDistance == 0 && origin != nil {
+		for i := range shops {
+			d := geo.Distance(*origin, geo.Point{Lat: shops[i].Lat, Lng: shops[i].Lng})
+			p.MaxDistance = max(p.MaxDistance, int(math.Ceil(d)))
+		}
+	}
+	if p.BudgetYen == 0 {
+		lo, hi := 0, 0
+		for i := range shops {
+			if yen := shops[i].Budget.AverageYen(); yen > 0 {
+				if lo == 0 || yen < lo {
+					lo = yen
+				}
+				hi = max(hi, yen)
+			}
+		}
+		p.BudgetYen = (lo + hi) / 2
+	}
+}
+
+// Weigh gives every shop a weight of 1 plus its score from p, the same
+// score hpp search --rank sorts by, and at least 0.
+func Weigh(shops []api.Shop, origin *geo.Point, p *rank.Profile) []Candidate {
+	cands := make([]Candidate, len(shops))
+	for i := range shops {
+		sc := p.Score(&shops[i], origin)
+		c := Candidate{Shop: shops[i], Weight: max(0, 1+sc.Score)}
+		for _, f := range sc.Breakdown {
+			if f.Points != 0 {
+				c.Factors = append(c.Factors, f)
+			}
+		}
+		cands[i] = c
+	}
+	return cands
+}
+
+// Choose draws n candidates without replacement, each with probability
+// proportional to its weight among those not drawn yet, or evenly when
+// none of them has any weight. It returns the picks in draw order with
+// their Chance set.
+func Choose(r *rand.Rand, cands []Candidate, n int) []Candidate {
+	pool := slices.Clone(cands)
+	var picks []Candidate
+	for len(picks) < n && len(pool) > 0 {
+		total := 0.0
+		for _, c := range pool {
+			total += c.Weight
+		}
+		weight := func(c Candidate) float64 { return c.Weight }
+		if total == 0 {
+			weight = func(Candidate) float64 { return 1 }
+			total = float64(len(pool))
+		}
+		x := r.Float64() * total
+		i := 0
+		for ; i < len(pool)-1; i++ {
+			if x < weight(pool[i]) {
+				break
+			}
+			x -= weight(pool[i])
+		}
+		c := pool[i]
+		c.Chance = weight(c) / total
+		picks = append(picks, c)
+		pool = slices.Delete(pool, i, i+1)
+	}
+	return picks
+}
+
+// Reason explains a pick from among n candidates in one sentence.
+func Reason(c Candidate, n int) string {
+	if len(c.Factors) == 0 {
+		return fmt.Sprintf("Drawn at random from %d candidates (%.1f%% chance).", n, c.Chance*100)
+	}
+	parts := make([]string, len(c.Factors))
+	for i, f := range c.Factors {
+		parts[i] = fmt.Sprintf("%s %s %+.2f", f.Factor, f.Value, f.Points)
+	}
+	return fmt.Sprintf("Drawn from %d candidates with a %.1f%% chance (weight %.2f: %s).",
+		n, c.Chance*100, c.Weight, strings.Join(parts, ", "))
+}
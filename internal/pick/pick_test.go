@@ -0,0 +1,124 @@
+package pick
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+	"github.com/jackchuka/hpp/internal/rank"
+)
+
+func TestParseWeights(t *testing.T) {
+	w, err := ParseWeights([]string{"distance=2", "private_room", "budget=0.5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.Distance != 2 || w.Amenities["private_room"] != 1 || w.Budget != 0.5 {
+		t.Fatalf("unexpected weights %+v", w)
+	}
+	for _, bad := range []string{"nearby=1", "budget=-1", "budget=lots", "budget=Inf", "distance=+inf", "distance=NaN", "distance=1e400"} {
+		if _, err := ParseWeights([]string{bad}); err == nil {
+			t.Fatalf("ParseWeights(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestWeigh(t *testing.T) {
+	origin := geo.Point{Lat: 35.6554, Lng: 139.7571}
+	shops := []api.Shop{
+		{ID: "near", Lat: 35.6554, Lng: 139.7571, Budget: api.Budget{Average: "4000円"}, PrivateRoom: "あり"},
+		{ID: "far", Lat: 35.6654, Lng: 139.7571, Budget: api.Budget{Average: "2000円"}, PrivateRoom: "なし"},
+		{ID: "unknown", Lat: 35.6604, Lng: 139.7571},
+	}
+	// The farthest shop and the budget midpoint set the limits.
+	p := rank.Profile{Distance: 2, Budget: 1, Amenities: map[string]float64{"private_room": 1}}
+	SetLimits(&p, shops, &origin)
+	if p.MaxDistance < 1100 || p.MaxDistance > 1120 || p.BudgetYen != 3000 {
+		t.Fatalf("unexpected limits %+v", p)
+	}
+	cands := Weigh(shops, &origin, &p)
+
+	want := map[string]float64{"near": 1 + 2 + 0.67 + 1, "far": 1 + 0 + 1, "unknown": 1 + 1}
+	for _, c := range cands {
+		if diff := c.Weight - want[c.Shop.ID]; diff > 0.01 || diff < -0.01 {
+			t.Fatalf("%s: weight %.2f, want %.2f (%+v)", c.Shop.ID, c.Weight, want[c.Shop.ID], c.Factors)
+		}
+	}
+	if f := cands[0].Factors; len(f) != 3 || f[0].Factor != Distance || f[0].Value != "0m" || f[1].Factor != Budget || f[2].Factor != "private_room" {
+		t.Fatalf("unexpected factors %+v", f)
+	}
+}
+
+func TestWeigh_BudgetSpread(t *testing.T) {
+	// One cheap lunch spot among pricier izakayas: the budget weight must
+	// still tell the izakayas apart.
+	shops := []api.Shop{
+		{ID: "lunch", Budget: api.Budget{Average: "1000円"}},
+		{ID: "izakaya3000", Budget: api.Budget{Average: "3000円"}},
+		{ID: "izakaya4000", Budget: api.Budget{Average: "4000円"}},
+		{ID: "izakaya5000", Budget: api.Budget{Average: "5000円"}},
+	}
+	p := rank.Profile{Budget: 1}
+	SetLimits(&p, shops, nil)
+	if p.BudgetYen != 3000 {
+		t.Fatalf("budget_yen %d, want the 3000円 midpoint", p.BudgetYen)
+	}
+	want := map[string]float64{"lunch": 2, "izakaya3000": 2, "izakaya4000": 1.67, "izakaya5000": 1.33}
+	for _, c := range Weigh(shops, nil, &p) {
+		if diff := c.Weight - want[c.Shop.ID]; diff > 0.01 || diff < -0.01 {
+			t.Fatalf("%s: weight %.2f, want %.2f", c.Shop.ID, c.Weight, want[c.Shop.ID])
+		}
+	}
+}
+
+func TestChoose(t *testing.T) {
+	cands := []Candidate{
+		{Shop: api.Shop{ID: "a"}, Weight: 1},
+		{Shop: api.Shop{ID: "b"}, Weight: 3},
+		{Shop: api.Shop{ID: "c"}, Weight: 0},
+	}
+	picks := Choose(rand.New(rand.NewPCG(1, 1)), cands, 5)
+	if len(picks) != 3 {
+		t.Fatalf("expected every candidate once, got %d picks", len(picks))
+	}
+	seen := map[string]bool{}
+	for _, p := range picks {
+		if seen[p.Shop.ID] {
+			t.Fatalf("%s picked twice", p.Shop.ID)
+		}
+		seen[p.Shop.ID] = true
+	}
+
+	// The same seed gives the same picks, and weights shift the odds.
+	counts := map[string]int{}
+	for i := range uint64(2000) {
+		p := Choose(rand.New(rand.NewPCG(i, i)), cands, 1)
+		counts[p[0].Shop.ID]++
+		if p[0].Chance != p[0].Weight/4 {
+			t.Fatalf("chance %.2f for weight %.0f", p[0].Chance, p[0].Weight)
+		}
+	}
+	if counts["c"] != 0 || counts["b"] < 1300 || counts["b"] > 1700 {
+		t.Fatalf("unexpected draw counts %v", counts)
+	}
+	if p := Choose(rand.New(rand.NewPCG(1, 1)), cands[2:], 1); p[0].Chance != 1 {
+		t.Fatalf("expected an even draw among unweighted candidates, got %+v", p)
+	}
+	a := Choose(rand.New(rand.NewPCG(7, 7)), cands, 2)
+	b := Choose(rand.New(rand.NewPCG(7, 7)), cands, 2)
+	if a[0].Shop.ID != b[0].Shop.ID || a[1].Shop.ID != b[1].Shop.ID {
+		t.Fatal("expected the same seed to give the same picks")
+	}
+}
+
+func TestReason(t *testing.T) {
+	c := Candidate{Weight: 2, Chance: 0.25, Factors: []rank.Component{{Factor: Distance, Value: "350m", Points: 1}}}
+	if got := Reason(c, 8); !strings.Contains(got, "25.0% chance") || !strings.Contains(got, "distance 350m +1.00") {
+		t.Fatalf("unexpected reason %q", got)
+	}
+	if got := Reason(Candidate{Weight: 1, Chance: 0.125}, 8); !strings.Contains(got, "at random from 8") {
+		t.Fatalf("unexpected reason %q", got)
+	}
+}
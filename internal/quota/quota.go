@@ -0,0 +1,185 @@
+package quota
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Actions taken once a cap is reached.
+const (
+	ActionWarn   = "warn"
+	ActionRefuse = "refuse"
+)
+
+// ErrCapReached is returned by Tracker.Take when a cap is reached and the
+// action is ActionRefuse.
+var ErrCapReached = errors.New("request cap reached")
+
+// flushEvery is how many requests a Tracker counts in memory before
+// writing them to the store.
+const flushEvery = 10
+
+// jst is the zone days and months are counted in.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// Caps are soft limits on the requests made with one API key. Zero means
+// no cap.
+type Caps struct {
+	Daily   int    `json:"daily"`
+	Monthly int    `json:"monthly"`
+	Action  string `json:"action"` // ActionWarn or ActionRefuse
+}
+
+// Validate reports an unknown action or a negative cap.
+func (c Caps) Validate() error {
+	if c.Daily < 0 || c.Monthly < 0 {
+		return fmt.Errorf("caps must not be negative")
+	}
+	if c.Action != ActionWarn && c.Action != ActionRefuse {
+		return fmt.Errorf("unknown cap action %q (want %s or %s)", c.Action, ActionWarn, ActionRefuse)
+	}
+	return nil
+}
+
+// exceeded describes the first cap that today's and this month's counts
+// have reached, or returns "".
+func (c Caps) exceeded(today, month int) string {
+	switch {
+	case c.Daily > 0 && today >= c.Daily:
+		return fmt.Sprintf("daily cap of %d requests reached (%d used today)", c.Daily, today)
+	case c.Monthly > 0 && month >= c.Monthly:
+		return fmt.Sprintf("monthly cap of %d requests reached (%d used this month)", c.Monthly, month)
+	}
+	return ""
+}
+
+// KeyID identifies an API key in the usage file without storing the key.
+func KeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Day returns the date key (in JST) that t is counted under.
+func Day(t time.Time) string {
+	return t.In(jst).Format(time.DateOnly)
+}
+
+// Month returns the month key (in JST) that t is counted under.
+func Month(t time.Time) string {
+	return t.In(jst).Format("2006-01")
+}
+
+// Usage is the number of requests made with one key per JST day.
+type Usage struct {
+	Days map[string]int `json:"days"`
+}
+
+// Today returns the requests counted on the day of t.
+func (u *Usage) Today(t time.Time) int {
+	return u.Days[Day(t)]
+}
+
+// ThisMonth returns the requests counted in the month of t.
+func (u *Usage) ThisMonth(t time.Time) int {
+	return sumMonth(u.Days, Month(t))
+}
+
+func sumMonth(days map[string]int, month string) int {
+	n := 0
+	for day, c := range days {
+		if strings.HasPrefix(day, month) {
+			n += c
+		}
+	}
+	return n
+}
+
+// Tracker counts the requests of one process against the caps of one key.
+// Counts are kept in memory and written to the store every few requests
+// and on Flush. It is safe for concurrent use.
+type Tracker struct {
+	// Warn is called once when a cap is reached with ActionWarn.
+	Warn func(msg string)
+
+	store   *Store
+	key     string
+	caps    Caps
+	now     func() time.Time
+	mu      sync.Mutex
+	base    *Usage         // usage as last read from the store
+	pending map[string]int // requests per day not yet stored
+	unsaved int
+	warned  bool
+}
+
+// NewTracker loads the stored usage of apiKey and returns a tracker
+// enforcing caps.
+func NewTracker(store *Store, apiKey string, caps Caps) (*Tracker, error) {
+	key := KeyID(apiKey)
+	u, err := store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracker{
+		Warn:    func(string) {},
+		store:   store,
+		key:     key,
+		caps:    caps,
+		now:     time.Now,
+		base:    u,
+		pending: map[string]int{},
+	}, nil
+}
+
+// Take counts one request, or refuses it once a cap is reached and the
+// action is ActionRefuse.
+func (t *Tracker) Take() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	day, month := Day(now), Month(now)
+	today := t.base.Days[day] + t.pending[day]
+	thisMonth := sumMonth(t.base.Days, month) + sumMonth(t.pending, month)
+	if msg := t.caps.exceeded(today, thisMonth); msg != "" {
+		if t.caps.Action == ActionRefuse {
+			return fmt.Errorf("%w: %s", ErrCapReached, msg)
+		}
+		if !t.warned {
+			t.warned = true
+			t.Warn(msg)
+		}
+	}
+	t.pending[day]++
+	if t.unsaved++; t.unsaved >= flushEvery {
+		// A failed write is retried by the next request and by Flush.
+		_ = t.flush()
+	}
+	return nil
+}
+
+// Flush writes the counted requests to the store and picks up requests
+// counted by other processes.
+func (t *Tracker) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.flush()
+}
+
+func (t *Tracker) flush() error {
+	if t.unsaved == 0 {
+		return nil
+	}
+	u, err := t.store.Add(t.key, t.pending, t.now())
+	if err != nil {
+		return err
+	}
+	t.base = u
+	t.pending = map[string]int{}
+	t.unsaved = 0
+	return nil
+}
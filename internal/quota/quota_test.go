@@ -0,0 +1,100 @@
+package quota
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestTracker(t *testing.T, caps Caps, now time.Time) (*Tracker, *Store) {
+	t.Helper()
+	store := &Store{Path: filepath.Join(t.TempDir(), "usage.json")}
+	tr, err := NewTracker(store, "secret", caps)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tr.now = func() time.Time { return now }
+	return tr, store
+}
+
+func TestTracker_Refuse(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, jst)
+	tr, store := newTestTracker(t, Caps{Daily: 3, Action: ActionRefuse}, now)
+	for range 3 {
+		if err := tr.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := tr.Take(); !errors.Is(err, ErrCapReached) {
+		t.Fatalf("expected cap error, got %v", err)
+	}
+	if err := tr.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, err := store.Load(KeyID("secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Today(now) != 3 || u.ThisMonth(now) != 3 {
+		t.Fatalf("expected 3 stored requests, got %+v", u)
+	}
+}
+
+func TestTracker_WarnOnce(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, jst)
+	tr, _ := newTestTracker(t, Caps{Monthly: 2, Action: ActionWarn}, now)
+	var warnings []string
+	tr.Warn = func(msg string) { warnings = append(warnings, msg) }
+	for range 5 {
+		if err := tr.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(warnings) != 1 || warnings[0] != "monthly cap of 2 requests reached (2 used this month)" {
+		t.Fatalf("expected one warning, got %q", warnings)
+	}
+}
+
+func TestTracker_SharesStore(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, jst)
+	a, store := newTestTracker(t, Caps{Daily: 15, Action: ActionRefuse}, now)
+	for range flushEvery { // the tenth request is written through
+		if err := a.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	b, err := NewTracker(store, "secret", Caps{Daily: 15, Action: ActionRefuse})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b.now = a.now
+	for range 5 {
+		if err := b.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := b.Take(); !errors.Is(err, ErrCapReached) {
+		t.Fatalf("expected the second process to see the first one's requests, got %v", err)
+	}
+}
+
+func TestDay_JST(t *testing.T) {
+	// 20:00 UTC on Oct 31 is already Nov 1 in Japan.
+	ts := time.Date(2026, 10, 31, 20, 0, 0, 0, time.UTC)
+	if Day(ts) != "2026-11-01" || Month(ts) != "2026-11" {
+		t.Fatalf("expected JST day, got %s %s", Day(ts), Month(ts))
+	}
+}
+
+func TestCaps_Validate(t *testing.T) {
+	if err := (Caps{Daily: 10, Action: ActionWarn}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (Caps{Action: "block"}).Validate(); err == nil {
+		t.Fatal("expected unknown action to fail")
+	}
+	if err := (Caps{Daily: -1, Action: ActionWarn}).Validate(); err == nil {
+		t.Fatal("expected negative cap to fail")
+	}
+}
@@ -0,0 +1,80 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/jackchuka/hpp/internal/jsonfile"
+)
+
+// Store keeps request counts per key and day in a JSON file. Updates are
+// serialized across processes with a lock file.
+type Store struct {
+	Path string
+}
+
+type usageFile struct {
+	Keys map[string]*Usage `json:"keys"`
+}
+
+// Load returns the stored usage of the key with the given KeyID.
+func (s *Store) Load(key string) (*Usage, error) {
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return f.usage(key), nil
+}
+
+// Add adds counts per day to the usage of key, drops days before the
+// previous month and returns the updated usage.
+func (s *Store) Add(key string, counts map[string]int, now time.Time) (*Usage, error) {
+	f := &usageFile{}
+	var u *Usage
+	err := s.file().Update(f, func() error {
+		u = f.usage(key)
+		for day, n := range counts {
+			u.Days[day] += n
+		}
+		y, m, _ := now.In(jst).Date()
+		oldest := Day(time.Date(y, m-1, 1, 12, 0, 0, 0, jst))
+		for _, u := range f.Keys {
+			for day := range u.Days {
+				if day < oldest {
+					delete(u.Days, day)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Store) file() *jsonfile.File {
+	return &jsonfile.File{Path: s.Path, Name: "usage"}
+}
+
+func (s *Store) read() (*usageFile, error) {
+	f := &usageFile{}
+	if err := s.file().Read(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *usageFile) usage(key string) *Usage {
+	if f.Keys == nil {
+		f.Keys = map[string]*Usage{}
+	}
+	u := f.Keys[key]
+	if u == nil {
+		u = &Usage{}
+		f.Keys[key] = u
+	}
+	if u.Days == nil {
+		u.Days = map[string]int{}
+	}
+	return u
+}
@@ -0,0 +1,48 @@
+package quota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_AddPrunes(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "hpp", "usage.json")}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, jst)
+	if _, err := store.Add("k1", map[string]int{"2026-08-31": 4, "2026-09-01": 2, "2026-10-19": 1}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u, err := store.Add("k1", map[string]int{"2026-10-19": 2}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := u.Days["2026-08-31"]; ok {
+		t.Fatalf("expected days before last month to be dropped, got %v", u.Days)
+	}
+	if u.Days["2026-09-01"] != 2 || u.Today(now) != 3 {
+		t.Fatalf("unexpected usage %v", u.Days)
+	}
+	other, err := store.Load("k2")
+	if err != nil || len(other.Days) != 0 {
+		t.Fatalf("expected keys to be counted separately, got %v %v", other, err)
+	}
+	if _, err := os.Stat(store.Path + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected lock to be released, got %v", err)
+	}
+}
+
+func TestStore_StaleLock(t *testing.T) {
+	store := &Store{Path: filepath.Join(t.TempDir(), "usage.json")}
+	lock := store.Path + ".lock"
+	if err := os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add("k", map[string]int{"2026-10-19": 1}, time.Now()); err != nil {
+		t.Fatalf("expected stale lock to be taken over, got %v", err)
+	}
+}
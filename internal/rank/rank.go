@@ -0,0 +1,150 @@
+package rank
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+)
+
+// DefaultMaxDistance is the distance in meters at which the distance fit
+// reaches 0 when a profile does not set one.
+const DefaultMaxDistance = 1000
+
+// Profile weighs what makes a shop a good fit. Each factor scores a fit
+// from 0 to 1, multiplied by its weight; amenity and genre weights are
+// added when the shop has the amenity or genre, and may be negative.
+// hpp search --rank sorts by the score and hpp pick draws by it.
+type Profile struct {
+	Distance    float64            `json:"distance,omitempty"`     // closer to the search point
+	MaxDistance int                `json:"max_distance,omitempty"` // meters at which the distance fit is 0
+	Budget      float64            `json:"budget,omitempty"`       // average spend within BudgetYen
+	BudgetYen   int                `json:"budget_yen,omitempty"`   // per person
+	Capacity    float64            `json:"capacity,omitempty"`     // seats for People
+	People      int                `json:"people,omitempty"`
+	Amenities   map[string]float64 `json:"amenities,omitempty"` // e.g. private_room, non_smoking
+	Genres      map[string]float64 `json:"genres,omitempty"`    // genre or sub-genre code or name
+}
+
+// Default is the profile used for --rank when the config defines none
+// named "default": closest first.
+var Default = Profile{Distance: 1}
+
+// Validate reports unknown amenities, negative limits and weighted factors
+// missing their target.
+func (p *Profile) Validate() error {
+	if p.MaxDistance < 0 || p.BudgetYen < 0 || p.People < 0 {
+		return fmt.Errorf("max_distance, budget_yen and people must not be negative")
+	}
+	if p.Budget != 0 && p.BudgetYen == 0 {
+		return fmt.Errorf("budget is weighted but budget_yen is not set")
+	}
+	if p.Capacity != 0 && p.People == 0 {
+		return fmt.Errorf("capacity is weighted but people is not set")
+	}
+	for name := range p.Amenities {
+		if !slices.ContainsFunc(api.Amenities, func(a api.Amenity) bool { return a.Name == name }) {
+			return fmt.Errorf("unknown amenity %q", name)
+		}
+	}
+	return nil
+}
+
+// Component is one factor's part of a score.
+type Component struct {
+	Factor string  `json:"factor"`
+	Value  string  `json:"value"`  // the shop's value, e.g. "350m" or "3500円"
+	Fit    float64 `json:"fit"`    // 0 to 1
+	Points float64 `json:"points"` // fit times weight
+}
+
+// Score is a shop's total score and how it was reached.
+type Score struct {
+	Score     float64     `json:"score"`
+	Breakdown []Component `json:"breakdown"`
+}
+
+// Score scores s. Distance is only scored when origin is set.
+func (p *Profile) Score(s *api.Shop, origin *geo.Point) Score {
+	var sc Score
+	add := func(factor, value string, fit, weight float64) {
+		c := Component{Factor: factor, Value: value, Fit: round(fit), Points: round(fit * weight)}
+		sc.Breakdown = append(sc.Breakdown, c)
+		sc.Score += fit * weight
+	}
+
+	if p.Distance != 0 && origin != nil {
+		maxDist := float64(cmp.Or(p.MaxDistance, DefaultMaxDistance))
+		d := geo.Distance(*origin, geo.Point{Lat: s.Lat, Lng: s.Lng})
+		add("distance", geo.FormatDistance(d), max(0, 1-d/maxDist), p.Distance)
+	}
+	if p.Budget != 0 {
+		yen := s.Budget.AverageYen()
+		fit, value := 0.0, "unknown"
+		if yen > 0 {
+			// Full fit within budget, falling to 0 at twice the budget.
+			target := float64(p.BudgetYen)
+			fit, value = max(0, min(1, 2-float64(yen)/target)), fmt.Sprintf("%d円", yen)
+		}
+		add("budget", value, fit, p.Budget)
+	}
+	if p.Capacity != 0 {
+		seats := max(int(s.Capacity), int(s.PartyCapacity))
+		fit, value := 0.0, "unknown"
+		if seats > 0 {
+			fit, value = min(1, float64(seats)/float64(p.People)), fmt.Sprintf("%d seats", seats)
+		}
+		add("capacity", value, fit, p.Capacity)
+	}
+	for _, name := range sortedKeys(p.Amenities) {
+		for _, a := range api.Amenities {
+			if a.Name != name {
+				continue
+			}
+			v := a.Shop(s)
+			fit := 0.0
+			if api.HasAmenity(v) {
+				fit = 1
+			}
+			add(name, v, fit, p.Amenities[name])
+		}
+	}
+	for _, g := range sortedKeys(p.Genres) {
+		if slices.ContainsFunc([]string{s.Genre.Code, s.Genre.Name, s.SubGenre.Code, s.SubGenre.Name},
+			func(v string) bool { return v != "" && strings.EqualFold(v, g) }) {
+			add("genre", g, 1, p.Genres[g])
+		}
+	}
+	sc.Score = round(sc.Score)
+	return sc
+}
+
+// Rank scores the shops and sorts them by score, highest first, keeping
+// the API order between equal scores. It returns the scores by shop ID.
+func (p *Profile) Rank(shops []api.Shop, origin *geo.Point) map[string]Score {
+	scores := make(map[string]Score, len(shops))
+	for i := range shops {
+		scores[shops[i].ID] = p.Score(&shops[i], origin)
+	}
+	slices.SortStableFunc(shops, func(a, b api.Shop) int {
+		return cmp.Compare(scores[b.ID].Score, scores[a.ID].Score)
+	})
+	return scores
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,86 @@
+package rank
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+)
+
+func TestScore(t *testing.T) {
+	p := Profile{
+		Distance:  2,
+		Budget:    1,
+		BudgetYen: 4000,
+		Capacity:  1,
+		People:    20,
+		Amenities: map[string]float64{"private_room": 1.5, "karaoke": -1},
+		Genres:    map[string]float64{"居酒屋": 0.5},
+	}
+	origin := geo.Point{Lat: 35.6554, Lng: 139.7571}
+	s := api.Shop{
+		Lat: 35.6554, Lng: 139.7571,
+		Genre:       api.CodeName{Code: "G001", Name: "居酒屋"},
+		Budget:      api.Budget{Average: "5000円"},
+		Capacity:    10,
+		PrivateRoom: "あり",
+		Karaoke:     "あり",
+	}
+
+	sc := p.Score(&s, &origin)
+	// distance 1*2 + budget 0.75*1 + capacity 0.5*1 + private_room 1.5 - karaoke 1 + genre 0.5
+	if sc.Score != 4.25 {
+		t.Fatalf("score %.2f, want 4.25: %+v", sc.Score, sc.Breakdown)
+	}
+	want := []string{"distance", "budget", "capacity", "karaoke", "private_room", "genre"}
+	if len(sc.Breakdown) != len(want) {
+		t.Fatalf("unexpected breakdown %+v", sc.Breakdown)
+	}
+	for i, c := range sc.Breakdown {
+		if c.Factor != want[i] {
+			t.Fatalf("breakdown[%d] = %s, want %s", i, c.Factor, want[i])
+		}
+	}
+	if c := sc.Breakdown[1]; c.Value != "5000円" || c.Fit != 0.75 {
+		t.Fatalf("unexpected budget component %+v", c)
+	}
+
+	// Without a search point, distance is not scored.
+	if sc := p.Score(&s, nil); sc.Score != 2.25 || sc.Breakdown[0].Factor != "budget" {
+		t.Fatalf("unexpected score without origin %+v", sc)
+	}
+}
+
+func TestRank(t *testing.T) {
+	p := Profile{Amenities: map[string]float64{"non_smoking": 1}}
+	shops := []api.Shop{
+		{ID: "a", NonSmoking: "なし"},
+		{ID: "b", NonSmoking: "全面禁煙"},
+		{ID: "c"},
+		{ID: "d", NonSmoking: "一部禁煙"},
+	}
+	scores := p.Rank(shops, nil)
+	got := ""
+	for _, s := range shops {
+		got += s.ID
+	}
+	if got != "bdac" || scores["b"].Score != 1 || scores["a"].Score != 0 {
+		t.Fatalf("ranked %s with scores %v", got, scores)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []Profile{
+		{Budget: 1},
+		{Capacity: 1},
+		{MaxDistance: -1},
+		{Amenities: map[string]float64{"jacuzzi": 1}},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("expected %+v to be rejected", p)
+		}
+	}
+}
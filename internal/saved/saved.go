@@ -0,0 +1,136 @@
+package saved
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/jsonfile"
+)
+
+// ErrNotFound is returned for a name with no saved search.
+var ErrNotFound = errors.New("no saved search with that name")
+
+var nameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Search is a named gourmet search. Params are sent to the API as is;
+// Filters are the client-side search flags (e.g. --where), which have no
+// API parameter.
+type Search struct {
+	Name      string                  `json:"name"`
+	Params    api.GourmetSearchParams `json:"params"`
+	Filters   []string                `json:"filters,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// Store keeps saved searches in a JSON file, sorted by name.
+type Store struct {
+	Path string
+}
+
+type savedFile struct {
+	Searches []Search `json:"searches"`
+}
+
+// ValidName reports whether name can name a saved search.
+func ValidName(name string) error {
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("invalid name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+func (s *Store) file() *jsonfile.File {
+	return &jsonfile.File{Path: s.Path, Name: "saved searches"}
+}
+
+// List returns all saved searches.
+func (s *Store) List() ([]Search, error) {
+	var f savedFile
+	if err := s.file().Read(&f); err != nil {
+		return nil, err
+	}
+	return f.Searches, nil
+}
+
+// Get returns the search saved as name.
+func (s *Store) Get(name string) (*Search, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(list, func(x Search) bool { return x.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return &list[i], nil
+}
+
+// Put saves search, replacing any search with the same name, and reports
+// whether one was replaced.
+func (s *Store) Put(search Search) (replaced bool, err error) {
+	if err := ValidName(search.Name); err != nil {
+		return false, err
+	}
+	var f savedFile
+	err = s.file().Update(&f, func() error {
+		f.Searches = slices.DeleteFunc(f.Searches, func(x Search) bool {
+			if x.Name == search.Name {
+				replaced = true
+				return true
+			}
+			return false
+		})
+		f.Searches = append(f.Searches, search)
+		slices.SortFunc(f.Searches, func(a, b Search) int { return strings.Compare(a.Name, b.Name) })
+		return nil
+	})
+	return replaced, err
+}
+
+// Remove deletes the search saved as name.
+func (s *Store) Remove(name string) error {
+	var f savedFile
+	return s.file().Update(&f, func() error {
+		n := len(f.Searches)
+		f.Searches = slices.DeleteFunc(f.Searches, func(x Search) bool { return x.Name == name })
+		if len(f.Searches) == n {
+			return fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return nil
+	})
+}
+
+// Merge returns base with every parameter set in override replacing the
+// saved value. Unset parameters, including false booleans, keep base's,
+// except the amenities named in off (e.g. "non_smoking"), which are
+// turned off.
+func Merge(base, override api.GourmetSearchParams, off ...string) (api.GourmetSearchParams, error) {
+	fields := map[string]json.RawMessage{}
+	for _, p := range []api.GourmetSearchParams{base, override} {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return api.GourmetSearchParams{}, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return api.GourmetSearchParams{}, err
+		}
+	}
+	for _, name := range off {
+		delete(fields, name)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return api.GourmetSearchParams{}, err
+	}
+	var out api.GourmetSearchParams
+	if err := json.Unmarshal(data, &out); err != nil {
+		return api.GourmetSearchParams{}, err
+	}
+	return out, nil
+}
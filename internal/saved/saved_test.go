@@ -0,0 +1,85 @@
+package saved
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestStore(t *testing.T) {
+	s := &Store{Path: filepath.Join(t.TempDir(), "hpp", "saved.json")}
+	if list, err := s.List(); err != nil || len(list) != 0 {
+		t.Fatalf("expected no searches, got %v %v", list, err)
+	}
+
+	kw := "ramen"
+	for _, name := range []string{"lunch", "dinner"} {
+		if _, err := s.Put(Search{Name: name, Params: api.GourmetSearchParams{Keyword: &kw}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	replaced, err := s.Put(Search{Name: "lunch", Params: api.GourmetSearchParams{Lunch: true}, Filters: []string{"--no-karaoke"}})
+	if err != nil || !replaced {
+		t.Fatalf("expected lunch to be replaced, got %v %v", replaced, err)
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "dinner" || list[1].Name != "lunch" {
+		t.Fatalf("expected searches sorted by name, got %+v", list)
+	}
+	got, err := s.Get("lunch")
+	if err != nil || !got.Params.Lunch || got.Params.Keyword != nil || got.Filters[0] != "--no-karaoke" {
+		t.Fatalf("unexpected search %+v %v", got, err)
+	}
+
+	if err := s.Remove("dinner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := s.Get("dinner"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.Remove("dinner"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	for _, name := range []string{"lunch", "hamamatsucho-lunch_2", "a.b"} {
+		if err := ValidName(name); err != nil {
+			t.Fatalf("expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-x", "two words", "../x"} {
+		if ValidName(name) == nil {
+			t.Fatalf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	kw, count, newCount := "ramen", 10, 5
+	base := api.GourmetSearchParams{Keyword: &kw, Count: &count, Genre: []string{"G013"}, Lunch: true}
+	got, err := Merge(base, api.GourmetSearchParams{Count: &newCount, Genre: []string{"G001", "G002"}, WiFi: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *got.Keyword != "ramen" || *got.Count != 5 || len(got.Genre) != 2 || !got.Lunch || !got.WiFi {
+		t.Fatalf("unexpected merge %+v", got)
+	}
+	if *base.Count != 10 || len(base.Genre) != 1 {
+		t.Fatalf("expected base to be unchanged, got %+v", base)
+	}
+
+	got, err = Merge(base, api.GourmetSearchParams{}, "lunch")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Lunch || *got.Keyword != "ramen" {
+		t.Fatalf("expected lunch to be turned off, got %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// cache is a size-bounded in-memory TTL cache of upstream results. Values
+// are shared between requests and must not be modified.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	max     int
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+func newCache(ttl time.Duration, max int) *cache {
+	return &cache{ttl: ttl, max: max, entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+func (c *cache) get(key string) (any, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *cache) set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.max {
+		// Drop expired entries, then the one closest to expiry if still full.
+		var oldest string
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			} else if oldest == "" || e.expires.Before(c.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		if len(c.entries) >= c.max {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+var errFlightPanic = errors.New("upstream request panicked")
+
+// flightGroup coalesces concurrent calls with the same key into one.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// do runs fn once per key at a time; callers arriving while it runs wait
+// for and share its result. shared reports whether the result was shared.
+func (g *flightGroup) do(key string, fn func() (any, error)) (value any, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.value, c.err, true
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	// Even if fn panics, release the waiters and let later calls run.
+	returned := false
+	defer func() {
+		if !returned {
+			c.err = errFlightPanic
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.value, c.err = fn()
+	returned = true
+	return c.value, c.err, false
+}
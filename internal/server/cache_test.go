@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := newCache(time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.set("a", 1)
+	now = now.Add(10 * time.Second)
+	c.set("b", 2)
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a to be cached, got %v %v", v, ok)
+	}
+	// Full: the entry closest to expiry is evicted.
+	c.set("c", 3)
+	if _, ok := c.get("a"); ok {
+		t.Fatalf("expected a to be evicted")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := c.get("b"); ok {
+		t.Fatalf("expected b to expire")
+	}
+
+	off := newCache(0, 10)
+	off.set("a", 1)
+	if _, ok := off.get("a"); ok {
+		t.Fatalf("expected zero TTL to disable caching")
+	}
+}
+
+func TestFlightGroup_Panic(t *testing.T) {
+	var g flightGroup
+	started, release := make(chan struct{}), make(chan struct{})
+	waited := make(chan error)
+	go func() {
+		defer func() { _ = recover() }()
+		g.do("k", func() (any, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+	go func() {
+		_, err, _ := g.do("k", func() (any, error) { return nil, nil })
+		waited <- err
+	}()
+	// Let the second call join the first before it panics.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	if err := <-waited; err == nil {
+		t.Fatal("expected the waiting call to fail")
+	}
+	if v, err, shared := g.do("k", func() (any, error) { return 1, nil }); v != 1 || err != nil || shared {
+		t.Fatalf("expected a fresh call after the panic, got %v %v %v", v, err, shared)
+	}
+}
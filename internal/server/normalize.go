@@ -0,0 +1,154 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// ShopList is the normalized response of /shops.
+type ShopList struct {
+	Available int    `json:"results_available"`
+	Returned  int    `json:"results_returned"`
+	Start     int    `json:"results_start"`
+	Shops     []Shop `json:"shops"`
+}
+
+// Shop is a normalized restaurant: numbers are numbers, amenities are
+// booleans and the access text is parsed into routes.
+type Shop struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	NameKana       string            `json:"name_kana"`
+	Catch          string            `json:"catch"`
+	Address        string            `json:"address"`
+	StationName    string            `json:"station_name"`
+	Lat            float64           `json:"lat"`
+	Lng            float64           `json:"lng"`
+	Genre          api.CodeName      `json:"genre"`
+	SubGenre       *api.CodeName     `json:"sub_genre,omitempty"`
+	Budget         Budget            `json:"budget"`
+	Capacity       int               `json:"capacity"`
+	PartyCapacity  int               `json:"party_capacity"`
+	Access         string            `json:"access"`
+	AccessRoutes   []api.AccessRoute `json:"access_routes"`
+	Open           string            `json:"open"`
+	Close          string            `json:"close"`
+	Areas          Areas             `json:"areas"`
+	Amenities      map[string]bool   `json:"amenities"`
+	KtaiCoupon     int               `json:"ktai_coupon"`
+	OtherMemo      string            `json:"other_memo,omitempty"`
+	ShopDetailMemo string            `json:"shop_detail_memo,omitempty"`
+	URL            string            `json:"url"`
+	CouponURL      string            `json:"coupon_url,omitempty"`
+	LogoImage      string            `json:"logo_image,omitempty"`
+	Photo          string            `json:"photo,omitempty"`
+}
+
+// Budget is a shop's budget band with its yen bounds parsed.
+type Budget struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Average    string `json:"average"`
+	Memo       string `json:"memo,omitempty"`
+	MinYen     int    `json:"min_yen"`
+	MaxYen     int    `json:"max_yen"`
+	AverageYen int    `json:"average_yen"`
+}
+
+// Areas is a shop's place in the area hierarchy, broadest first.
+type Areas struct {
+	LargeService api.CodeName `json:"large_service"`
+	Service      api.CodeName `json:"service"`
+	Large        api.CodeName `json:"large"`
+	Middle       api.CodeName `json:"middle"`
+	Small        api.CodeName `json:"small"`
+}
+
+// List is the normalized response of every master list endpoint.
+type List[T any] struct {
+	Count int `json:"count"`
+	Items []T `json:"items"`
+}
+
+func newList[T any](items []T) List[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return List[T]{Count: len(items), Items: items}
+}
+
+func normalizeResults(r *api.GourmetResults) ShopList {
+	returned, err := strconv.Atoi(r.ResultsReturned)
+	if err != nil {
+		returned = len(r.Shops)
+	}
+	list := ShopList{
+		Available: r.ResultsAvailable,
+		Returned:  returned,
+		Start:     r.ResultsStart,
+		Shops:     make([]Shop, len(r.Shops)),
+	}
+	for i := range r.Shops {
+		list.Shops[i] = normalizeShop(r.Shops[i])
+	}
+	return list
+}
+
+// normalizeShop converts a shop as returned by the API. It takes a copy so
+// cached results are never modified.
+func normalizeShop(s api.Shop) Shop {
+	s.ParseAccess()
+	lo, hi := s.Budget.Range()
+	n := Shop{
+		ID:          s.ID,
+		Name:        s.Name,
+		NameKana:    s.NameKana,
+		Catch:       s.Catch,
+		Address:     s.Address,
+		StationName: s.StationName,
+		Lat:         s.Lat,
+		Lng:         s.Lng,
+		Genre:       s.Genre,
+		Budget: Budget{
+			Code:       s.Budget.Code,
+			Name:       s.Budget.Name,
+			Average:    s.Budget.Average,
+			Memo:       s.Budget.BudgetMemo,
+			MinYen:     lo,
+			MaxYen:     hi,
+			AverageYen: s.Budget.AverageYen(),
+		},
+		Capacity:      int(s.Capacity),
+		PartyCapacity: int(s.PartyCapacity),
+		Access:        s.Access,
+		AccessRoutes:  s.AccessRoutes,
+		Open:          s.Open,
+		Close:         s.Close,
+		Areas: Areas{
+			LargeService: s.LargeServiceArea,
+			Service:      s.ServiceArea,
+			Large:        s.LargeArea,
+			Middle:       s.MiddleArea,
+			Small:        s.SmallArea,
+		},
+		Amenities:      make(map[string]bool, len(api.Amenities)),
+		KtaiCoupon:     int(s.KtaiCoupon),
+		OtherMemo:      s.OtherMemo,
+		ShopDetailMemo: s.ShopDetailMemo,
+		URL:            s.URLs.PC,
+		CouponURL:      s.CouponURLs.SP,
+		LogoImage:      s.LogoImage,
+		Photo:          s.Photo.PC.L,
+	}
+	if s.SubGenre.Code != "" {
+		n.SubGenre = &s.SubGenre
+	}
+	if n.AccessRoutes == nil {
+		n.AccessRoutes = []api.AccessRoute{}
+	}
+	for _, a := range api.Amenities {
+		n.Amenities[a.Name] = api.HasAmenity(a.Shop(&s))
+	}
+	return n
+}
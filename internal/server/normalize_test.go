@@ -0,0 +1,51 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestNormalizeResults(t *testing.T) {
+	r := &api.GourmetResults{
+		ResultsAvailable: 120,
+		ResultsReturned:  "1",
+		ResultsStart:     1,
+		Shops: []api.Shop{{
+			ID:            "J001",
+			Budget:        api.Budget{Name: "3001～4000円", Average: "3500円"},
+			Capacity:      40,
+			PartyCapacity: 30,
+			Access:        "JR浜松町駅北口より徒歩3分",
+			WiFi:          "あり",
+			Karaoke:       "なし",
+			NonSmoking:    "全面禁煙",
+		}},
+	}
+	list := normalizeResults(r)
+	if list.Available != 120 || list.Returned != 1 || list.Start != 1 {
+		t.Fatalf("unexpected counts %+v", list)
+	}
+	s := list.Shops[0]
+	if s.Capacity != 40 || s.PartyCapacity != 30 {
+		t.Fatalf("unexpected capacities %d/%d", s.Capacity, s.PartyCapacity)
+	}
+	if s.Budget.MinYen != 3001 || s.Budget.MaxYen != 4000 || s.Budget.AverageYen != 3500 {
+		t.Fatalf("unexpected budget %+v", s.Budget)
+	}
+	if !s.Amenities["wifi"] || s.Amenities["karaoke"] || !s.Amenities["non_smoking"] {
+		t.Fatalf("unexpected amenities %v", s.Amenities)
+	}
+	if len(s.Amenities) != len(api.Amenities) {
+		t.Fatalf("expected every amenity, got %d", len(s.Amenities))
+	}
+	if len(s.AccessRoutes) != 1 || s.AccessRoutes[0].WalkMinutes != 3 {
+		t.Fatalf("unexpected access routes %+v", s.AccessRoutes)
+	}
+	if s.SubGenre != nil {
+		t.Fatalf("expected empty sub-genre to be omitted, got %+v", s.SubGenre)
+	}
+	if r.Shops[0].AccessRoutes != nil {
+		t.Fatalf("expected source shop to be left unmodified")
+	}
+}
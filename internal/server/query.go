@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// decodeQuery fills the params struct pointed to by out from query values,
+// using the same url tags that encode it for the upstream API. Slices accept
+// repeated or comma-separated values; booleans accept 1/0 and true/false.
+// Unknown parameters are rejected so typos are not silently ignored.
+func decodeQuery(vals url.Values, out any) error {
+	v := reflect.ValueOf(out).Elem()
+	t := v.Type()
+	fields := make(map[string]int, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("url"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	for name, values := range vals {
+		i, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+		if err := setField(v.Field(i), values); err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(f reflect.Value, values []string) error {
+	if f.Kind() == reflect.Slice {
+		var items []string
+		for _, v := range values {
+			for item := range strings.SplitSeq(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		f.Set(reflect.ValueOf(items))
+		return nil
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("expected a single value")
+	}
+	s := strings.TrimSpace(values[0])
+	if f.Kind() == reflect.Pointer {
+		p := reflect.New(f.Type().Elem())
+		if err := setScalar(p.Elem(), s); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	}
+	return setScalar(f, s)
+}
+
+func setScalar(f reflect.Value, s string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		f.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.SetInt(int64(n))
+	case reflect.Float64:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		f.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestDecodeQuery(t *testing.T) {
+	vals, _ := url.ParseQuery("keyword=ramen&lat=35.65&range=3&genre=G001,G002&genre=G013&wifi=1&lunch=false&ktai_coupon=0")
+	var p api.GourmetSearchParams
+	if err := decodeQuery(vals, &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *p.Keyword != "ramen" || *p.Lat != 35.65 || *p.Range != 3 || *p.KtaiCoupon != 0 {
+		t.Fatalf("unexpected scalar params %+v", p)
+	}
+	if !reflect.DeepEqual(p.Genre, []string{"G001", "G002", "G013"}) {
+		t.Fatalf("unexpected genres %v", p.Genre)
+	}
+	if !p.WiFi || p.Lunch {
+		t.Fatalf("unexpected booleans wifi=%v lunch=%v", p.WiFi, p.Lunch)
+	}
+}
+
+func TestDecodeQuery_Errors(t *testing.T) {
+	tests := []string{
+		"key=secret",
+		"keywrd=ramen",
+		"range=near",
+		"wifi=maybe",
+		"lat=1&lat=2",
+	}
+	for _, q := range tests {
+		vals, _ := url.ParseQuery(q)
+		var p api.GourmetSearchParams
+		if err := decodeQuery(vals, &p); err == nil {
+			t.Fatalf("%s: expected error", q)
+		}
+	}
+}
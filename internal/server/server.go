@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-querystring/query"
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/quota"
+)
+
+// Options configures a Server.
+type Options struct {
+	CacheTTL  time.Duration // how long upstream results are reused; 0 disables caching
+	CacheSize int           // max cached results
+	Logger    *slog.Logger  // cache results and upstream failures are logged here; nil discards them
+}
+
+// Server is a REST front end to the HotPepper API. It holds the API key,
+// normalizes responses, caches upstream calls and coalesces identical
+// concurrent requests. Upstream calls are paced by the client's Limiter.
+type Server struct {
+	client *api.Client
+	cache  *cache
+	flight flightGroup
+	logger *slog.Logger
+	mux    *http.ServeMux
+}
+
+func New(client *api.Client, opts Options) *Server {
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = 1000
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	s := &Server{
+		client: client,
+		cache:  newCache(opts.CacheTTL, opts.CacheSize),
+		logger: opts.Logger,
+		mux:    http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("GET /shops", s.handleShops)
+	s.mux.HandleFunc("GET /shops/{id}", s.handleShop)
+
+	handleList[struct{}](s, "/genres", "/genre/v1/", func(r *api.GenreResponse) []api.Genre { return r.Results.Genres })
+	handleList[struct{}](s, "/budgets", "/budget/v1/", func(r *api.BudgetResponse) []api.BudgetMaster { return r.Results.Budgets })
+	handleList[struct{}](s, "/credit-cards", "/credit_card/v1/", func(r *api.CreditCardResponse) []api.CreditCard { return r.Results.CreditCards })
+	handleList[struct{}](s, "/service-areas", "/service_area/v1/", func(r *api.ServiceAreaResponse) []api.ServiceArea { return r.Results.ServiceAreas })
+	handleList[struct{}](s, "/service-areas/large", "/large_service_area/v1/", func(r *api.LargeServiceAreaResponse) []api.LargeServiceArea {
+		return r.Results.LargeServiceAreas
+	})
+	handleList[api.LargeAreaParams](s, "/areas/large", "/large_area/v1/", func(r *api.LargeAreaResponse) []api.LargeArea {
+		return r.Results.LargeAreas
+	})
+	handleList[api.MiddleAreaParams](s, "/areas/middle", "/middle_area/v1/", func(r *api.MiddleAreaResponse) []api.MiddleArea {
+		return r.Results.MiddleAreas
+	})
+	handleList[api.SmallAreaParams](s, "/areas/small", "/small_area/v1/", func(r *api.SmallAreaResponse) []api.SmallArea {
+		return r.Results.SmallAreas
+	})
+	handleList[api.SpecialParams](s, "/specials", "/special/v1/", func(r *api.SpecialResponse) []api.Special {
+		return r.Results.Specials
+	})
+	handleList[api.SpecialCategoryParams](s, "/special-categories", "/special_category/v1/", func(r *api.SpecialCategoryResponse) []api.SpecialCategory {
+		return r.Results.SpecialCategories
+	})
+}
+
+func (s *Server) handleShops(w http.ResponseWriter, r *http.Request) {
+	var p api.GourmetSearchParams
+	if err := decodeQuery(r.URL.Query(), &p); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := fetch[api.GourmetResponse](s, w, "/gourmet/v1/", p)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, normalizeResults(&resp.Results))
+}
+
+func (s *Server) handleShop(w http.ResponseWriter, r *http.Request) {
+	if len(r.URL.Query()) > 0 {
+		writeError(w, http.StatusBadRequest, "query parameters are not supported")
+		return
+	}
+	id := r.PathValue("id")
+	resp, err := fetch[api.GourmetResponse](s, w, "/gourmet/v1/", api.GourmetSearchParams{ID: []string{id}})
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+	for _, shop := range resp.Results.Shops {
+		if shop.ID == id {
+			writeJSON(w, http.StatusOK, normalizeShop(shop))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "shop "+id+" not found")
+}
+
+// handleList registers a master list endpoint whose query parameters are
+// decoded into P.
+func handleList[P, R, T any](s *Server, route, path string, items func(*R) []T) {
+	s.mux.HandleFunc("GET "+route, func(w http.ResponseWriter, r *http.Request) {
+		var p P
+		if err := decodeQuery(r.URL.Query(), &p); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		resp, err := fetch[R](s, w, path, p)
+		if err != nil {
+			s.writeUpstreamError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newList(items(resp)))
+	})
+}
+
+// fetch returns the decoded upstream response for path and params, served
+// from the cache when possible. Concurrent identical requests share one
+// upstream call. The X-Cache header reports hit, miss or shared.
+func fetch[R any](s *Server, w http.ResponseWriter, path string, params any) (*R, error) {
+	vals, err := query.Values(params)
+	if err != nil {
+		return nil, err
+	}
+	key := path + "?" + vals.Encode()
+	if v, ok := s.cache.get(key); ok {
+		s.logger.Debug("cache", "result", "hit", "request", key)
+		w.Header().Set("X-Cache", "hit")
+		return v.(*R), nil
+	}
+	v, err, shared := s.flight.do(key, func() (any, error) {
+		resp := new(R)
+		if err := s.client.Get(path, params, resp); err != nil {
+			return nil, err
+		}
+		s.cache.set(key, resp)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := "miss"
+	if shared {
+		result = "shared"
+	}
+	s.logger.Debug("cache", "result", result, "request", key)
+	w.Header().Set("X-Cache", result)
+	return v.(*R), nil
+}
+
+// writeUpstreamError reports a failed upstream call without leaking the API
+// key, which net/http errors include as part of the request URL.
+func (s *Server) writeUpstreamError(w http.ResponseWriter, err error) {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == 3000 {
+		// 3000: invalid parameter.
+		writeError(w, http.StatusBadRequest, apiErr.Message)
+		return
+	}
+	if errors.Is(err, quota.ErrCapReached) {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	msg := err.Error()
+	if s.client.APIKey != "" {
+		msg = strings.ReplaceAll(msg, s.client.APIKey, "REDACTED")
+	}
+	s.logger.Error("upstream error", "error", msg)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		writeError(w, http.StatusGatewayTimeout, "upstream request timed out")
+		return
+	}
+	writeError(w, http.StatusBadGateway, "upstream request failed")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"status": status, "message": msg}})
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// newTestServer returns a Server backed by a fake upstream API. release, if
+// non-nil, holds every upstream response until it is closed.
+func newTestServer(t *testing.T, opts Options, release chan struct{}) (*Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/gourmet/v1/":
+			if q.Get("range") == "9" {
+				_, _ = fmt.Fprint(w, `{"results":{"error":[{"code":3000,"message":"invalid range"}]}}`)
+				return
+			}
+			if q.Get("id") == "J404" {
+				_, _ = fmt.Fprint(w, `{"results":{"results_available":0,"results_returned":"0","results_start":1,"shop":[]}}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"results":{"results_available":1,"results_returned":"1","results_start":1,"shop":[{"id":"J001","name":"居酒屋 テスト","capacity":"40","wifi":"あり","karaoke":"なし"}]}}`)
+		case "/genre/v1/":
+			_, _ = fmt.Fprint(w, `{"results":{"results_available":2,"results_returned":"2","results_start":1,"genre":[{"code":"G001","name":"居酒屋"},{"code":"G002","name":"ダイニングバー"}]}}`)
+		case "/budget/v1/":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(upstream.Close)
+	c := api.NewClient("secret-key")
+	c.BaseURL = upstream.URL
+	return New(c, opts), &calls
+}
+
+func get(t *testing.T, s *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+	return rec
+}
+
+func TestServer_Shops(t *testing.T) {
+	s, _ := newTestServer(t, Options{}, nil)
+	rec := get(t, s, "/shops?keyword=izakaya&wifi=1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+	var list ShopList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if list.Returned != 1 || list.Shops[0].Capacity != 40 || !list.Shops[0].Amenities["wifi"] || list.Shops[0].Amenities["karaoke"] {
+		t.Fatalf("unexpected normalized list %+v", list)
+	}
+}
+
+func TestServer_ShopByID(t *testing.T) {
+	s, _ := newTestServer(t, Options{}, nil)
+	if rec := get(t, s, "/shops/J001"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"J001"`) {
+		t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body)
+	}
+	if rec := get(t, s, "/shops/J404"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestServer_Lists(t *testing.T) {
+	s, _ := newTestServer(t, Options{}, nil)
+	rec := get(t, s, "/genres")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), `{"count":2,"items":[{"code":"G001"`) {
+		t.Fatalf("unexpected response %d: %s", rec.Code, rec.Body)
+	}
+	if rec := get(t, s, "/genres?code=G001"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected unsupported parameter to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s, _ := newTestServer(t, Options{}, nil)
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/shops?keywrd=x", http.StatusBadRequest},
+		{"/shops?key=other", http.StatusBadRequest},
+		{"/shops?range=9", http.StatusBadRequest},
+		{"/budgets", http.StatusBadGateway},
+		{"/nope", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := get(t, s, tt.target)
+		if rec.Code != tt.status {
+			t.Fatalf("%s: got status %d, want %d", tt.target, rec.Code, tt.status)
+		}
+		if strings.Contains(rec.Body.String(), "secret-key") {
+			t.Fatalf("%s: response leaks the API key: %s", tt.target, rec.Body)
+		}
+	}
+}
+
+func TestServer_Cache(t *testing.T) {
+	s, calls := newTestServer(t, Options{CacheTTL: time.Minute}, nil)
+	if rec := get(t, s, "/genres"); rec.Header().Get("X-Cache") != "miss" {
+		t.Fatalf("expected miss, got %q", rec.Header().Get("X-Cache"))
+	}
+	if rec := get(t, s, "/genres"); rec.Header().Get("X-Cache") != "hit" {
+		t.Fatalf("expected hit, got %q", rec.Header().Get("X-Cache"))
+	}
+	// Same parameters in a different order share the cache entry.
+	get(t, s, "/shops?keyword=a&wifi=1")
+	get(t, s, "/shops?wifi=true&keyword=a")
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", n)
+	}
+}
+
+func TestServer_Coalesces(t *testing.T) {
+	release := make(chan struct{})
+	s, calls := newTestServer(t, Options{}, release)
+
+	const n = 5
+	var wg sync.WaitGroup
+	recs := make([]*httptest.ResponseRecorder, n)
+	for i := range n {
+		wg.Go(func() { recs[i] = get(t, s, "/genres") })
+	}
+	// Let every request join the in-flight call before it completes.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", got)
+	}
+	shared := 0
+	for _, rec := range recs {
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d", rec.Code)
+		}
+		if rec.Header().Get("X-Cache") == "shared" {
+			shared++
+		}
+	}
+	if shared != n-1 {
+		t.Fatalf("expected %d shared responses, got %d", n-1, shared)
+	}
+}
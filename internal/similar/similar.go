@@ -0,0 +1,149 @@
+package similar
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/hpp/internal/api"
+	"github.com/jackchuka/hpp/internal/geo"
+)
+
+// MaxDistance is the distance in meters at which shops stop scoring as
+// nearby.
+const MaxDistance = 2000
+
+// Weights of each part of the similarity score; they add up to 1.
+const (
+	amenityWeight  = 0.35
+	budgetWeight   = 0.2
+	subGenreWeight = 0.15
+	capacityWeight = 0.1
+	areaWeight     = 0.1
+	distanceWeight = 0.1
+)
+
+// Match is a shop scored for similarity to a reference shop.
+type Match struct {
+	Shop       api.Shop `json:"shop"`
+	Similarity float64  `json:"similarity"` // 0 to 1
+	Distance   int      `json:"distance"`   // meters from the reference shop
+	Reasons    []string `json:"reasons"`
+}
+
+// Rank scores the candidates for similarity to ref and returns them most
+// similar first. The reference shop itself is left out.
+func Rank(ref *api.Shop, cands []api.Shop) []Match {
+	refAmenities := amenities(ref)
+	var matches []Match
+	for i := range cands {
+		s := &cands[i]
+		if s.ID == ref.ID {
+			continue
+		}
+		d := geo.Distance(geo.Point{Lat: ref.Lat, Lng: ref.Lng}, geo.Point{Lat: s.Lat, Lng: s.Lng})
+		m := Match{Shop: *s, Distance: int(d)}
+
+		shared, union := jaccard(refAmenities, amenities(s))
+		if union > 0 {
+			m.Similarity += amenityWeight * float64(shared) / float64(union)
+		} else {
+			m.Similarity += amenityWeight
+		}
+		m.Reasons = append(m.Reasons, fmt.Sprintf("%d/%d amenities in common", shared, union))
+
+		if a, b := ref.Budget.AverageYen(), s.Budget.AverageYen(); a > 0 && b > 0 {
+			m.Similarity += budgetWeight * float64(min(a, b)) / float64(max(a, b))
+			m.Reasons = append(m.Reasons, fmt.Sprintf("budget %d円", b))
+		}
+		if ref.SubGenre.Code != "" && s.SubGenre.Code == ref.SubGenre.Code {
+			m.Similarity += subGenreWeight
+			m.Reasons = append(m.Reasons, "same sub-genre "+s.SubGenre.Name)
+		}
+		if a, b := max(int(ref.Capacity), int(ref.PartyCapacity)), max(int(s.Capacity), int(s.PartyCapacity)); a > 0 && b > 0 {
+			m.Similarity += capacityWeight * float64(min(a, b)) / float64(max(a, b))
+		}
+		if s.MiddleArea.Code == ref.MiddleArea.Code {
+			m.Similarity += areaWeight
+			m.Reasons = append(m.Reasons, "same area")
+		}
+		m.Similarity += distanceWeight * max(0, 1-d/MaxDistance)
+		m.Reasons = append(m.Reasons, geo.FormatDistance(d)+" away")
+
+		m.Similarity = float64(int(m.Similarity*1000+0.5)) / 1000
+		matches = append(matches, m)
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int { return cmp.Compare(b.Similarity, a.Similarity) })
+	return matches
+}
+
+// amenities returns the names of the amenities s has.
+func amenities(s *api.Shop) []string {
+	var names []string
+	for _, a := range api.Amenities {
+		if api.HasAmenity(a.Shop(s)) {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// jaccard returns the sizes of the intersection and union of two sets.
+func jaccard(a, b []string) (shared, union int) {
+	union = len(a)
+	for _, x := range b {
+		if slices.Contains(a, x) {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return shared, union
+}
+
+// ChainKey returns a shop name without its branch suffix, so branches of
+// a chain share a key: "鳥貴族 新橋店" and "鳥貴族 浜松町店" both give
+// "鳥貴族".
+func ChainKey(name string) string {
+	name = strings.TrimSpace(strings.ReplaceAll(name, "　", " "))
+	i := strings.LastIndex(name, " ")
+	if i > 0 && strings.HasSuffix(name, "店") {
+		return strings.TrimSpace(name[:i])
+	}
+	return name
+}
+
+// BandIndex orders budget bands by their lower bound and returns each
+// code's position, so neighboring bands differ by 1.
+func BandIndex(bands []api.Budget) map[string]int {
+	sorted := slices.Clone(bands)
+	slices.SortStableFunc(sorted, func(a, b api.Budget) int {
+		lo1, _ := a.Range()
+		lo2, _ := b.Range()
+		return cmp.Compare(lo1, lo2)
+	})
+	idx := make(map[string]int, len(sorted))
+	for i, b := range sorted {
+		idx[b.Code] = i
+	}
+	return idx
+}
+
+// NearbyBands returns the codes of the bands within spread bands of code,
+// cheapest first, or nil when code is not one of bands.
+func NearbyBands(bands []api.Budget, code string, spread int) []string {
+	idx := BandIndex(bands)
+	ref, ok := idx[code]
+	if !ok {
+		return nil
+	}
+	var codes []string
+	for c, i := range idx {
+		if max(i-ref, ref-i) <= spread {
+			codes = append(codes, c)
+		}
+	}
+	slices.SortFunc(codes, func(a, b string) int { return cmp.Compare(idx[a], idx[b]) })
+	return codes
+}
@@ -0,0 +1,77 @@
+package similar
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestRank(t *testing.T) {
+	ref := api.Shop{
+		ID: "ref", Lat: 35.6554, Lng: 139.7571,
+		SubGenre:    api.CodeName{Code: "G002"},
+		MiddleArea:  api.CodeName{Code: "Y005"},
+		Budget:      api.Budget{Average: "3500円"},
+		Capacity:    40,
+		PrivateRoom: "あり",
+		FreeDrink:   "あり",
+	}
+	cands := []api.Shop{
+		{ID: "ref"},
+		{ID: "far", Lat: 35.70, Lng: 139.80, MiddleArea: api.CodeName{Code: "Y010"}, Budget: api.Budget{Average: "8000円"}, Karaoke: "あり"},
+		{ID: "twin", Lat: 35.6556, Lng: 139.7571, SubGenre: api.CodeName{Code: "G002"}, MiddleArea: api.CodeName{Code: "Y005"},
+			Budget: api.Budget{Average: "3500円"}, Capacity: 40, PrivateRoom: "あり ：個室", FreeDrink: "あり"},
+		{ID: "close", Lat: 35.6560, Lng: 139.7571, MiddleArea: api.CodeName{Code: "Y005"}, Budget: api.Budget{Average: "3000円"}, PrivateRoom: "あり"},
+	}
+	matches := Rank(&ref, cands)
+	if len(matches) != 3 {
+		t.Fatalf("expected the reference shop to be left out, got %d matches", len(matches))
+	}
+	got := matches[0].Shop.ID + "," + matches[1].Shop.ID + "," + matches[2].Shop.ID
+	if got != "twin,close,far" {
+		t.Fatalf("ranked %s, want twin,close,far", got)
+	}
+	if s := matches[0].Similarity; s < 0.99 || s > 1 {
+		t.Fatalf("expected an identical shop to score about 1, got %.3f (%v)", s, matches[0].Reasons)
+	}
+	if matches[2].Similarity >= matches[1].Similarity {
+		t.Fatalf("expected far to score lowest: %+v", matches)
+	}
+}
+
+func TestChainKey(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"鳥貴族 新橋店", "鳥貴族"},
+		{"鳥貴族　浜松町店", "鳥貴族"},
+		{"Bar Shiba", "Bar Shiba"},
+		{"大戸屋", "大戸屋"},
+	}
+	for _, tt := range tests {
+		if got := ChainKey(tt.in); got != tt.want {
+			t.Fatalf("ChainKey(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBandIndex(t *testing.T) {
+	bands := []api.Budget{
+		{Code: "B003", Name: "3001～4000円"},
+		{Code: "B009", Name: "～500円"},
+		{Code: "B002", Name: "2001～3000円"},
+		{Code: "B014", Name: "30001円～"},
+	}
+	idx := BandIndex(bands)
+	if idx["B009"] != 0 || idx["B002"] != 1 || idx["B003"] != 2 || idx["B014"] != 3 {
+		t.Fatalf("unexpected band order %v", idx)
+	}
+	if got := NearbyBands(bands, "B002", 1); !slices.Equal(got, []string{"B009", "B002", "B003"}) {
+		t.Fatalf("NearbyBands = %v", got)
+	}
+	if got := NearbyBands(bands, "B014", 0); !slices.Equal(got, []string{"B014"}) {
+		t.Fatalf("NearbyBands = %v", got)
+	}
+	if got := NearbyBands(bands, "B999", 1); got != nil {
+		t.Fatalf("expected nil for an unknown band, got %v", got)
+	}
+}
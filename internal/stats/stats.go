@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+// Facets lists the facet names in output order.
+var Facets = []string{"genre", "sub_genre", "budget", "middle_area", "small_area", "station", "amenity"}
+
+// APIFacets lists the facets that can be counted with API filters.
+var APIFacets = []string{"genre", "budget", "middle_area", "small_area", "amenity"}
+
+// apiConcurrency is the number of count requests FromAPI runs at once.
+const apiConcurrency = 4
+
+// Count is the number of shops with one facet value.
+type Count struct {
+	Code  string `json:"code,omitempty"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Facet breaks shops down by one attribute, largest counts first. Total is
+// the number of shops the counts are out of; a shop can have several
+// amenities, so amenity counts may add up to more.
+type Facet struct {
+	Name   string  `json:"name"`
+	Total  int     `json:"total"`
+	Counts []Count `json:"counts"`
+}
+
+// Aggregate counts shops per value of each named facet. Shops without a
+// value for a facet are not counted under it.
+func Aggregate(shops []api.Shop, names []string) []Facet {
+	facets := make([]Facet, 0, len(names))
+	for _, name := range names {
+		counts := map[Count]int{}
+		for i := range shops {
+			for _, v := range values(&shops[i], name) {
+				counts[v]++
+			}
+		}
+		f := Facet{Name: name, Total: len(shops), Counts: []Count{}}
+		for v, n := range counts {
+			v.Count = n
+			f.Counts = append(f.Counts, v)
+		}
+		sortCounts(f.Counts)
+		facets = append(facets, f)
+	}
+	return facets
+}
+
+// values returns the facet values of s, with Count unset.
+func values(s *api.Shop, facet string) []Count {
+	codeName := func(c api.CodeName) []Count {
+		if c.Name == "" {
+			return nil
+		}
+		return []Count{{Code: c.Code, Name: c.Name}}
+	}
+	switch facet {
+	case "genre":
+		return codeName(s.Genre)
+	case "sub_genre":
+		return codeName(s.SubGenre)
+	case "budget":
+		return codeName(api.CodeName{Code: s.Budget.Code, Name: s.Budget.Name})
+	case "middle_area":
+		return codeName(s.MiddleArea)
+	case "small_area":
+		return codeName(s.SmallArea)
+	case "station":
+		return codeName(api.CodeName{Name: s.StationName})
+	case "amenity":
+		var out []Count
+		for _, a := range api.Amenities {
+			if api.HasAmenity(a.Shop(s)) {
+				out = append(out, Count{Name: a.Name})
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// FromAPI counts the shops matching p for each candidate value of facet by
+// adding the value as a filter and reading results_available, without
+// fetching the shops. Values the search already excludes are skipped, and
+// values without shops are left out. For "amenity", candidates are the
+// amenity names.
+func FromAPI(c *api.Client, p api.GourmetSearchParams, facet string, total int, candidates []api.CodeName) (*Facet, error) {
+	type job struct {
+		value  api.CodeName
+		params api.GourmetSearchParams
+	}
+	if !slices.Contains(APIFacets, facet) {
+		return nil, fmt.Errorf("facet %s cannot be counted by the API (use one of %v)", facet, APIFacets)
+	}
+	var jobs []job
+	for _, v := range candidates {
+		if q, ok := withValue(p, facet, v.Code); ok {
+			one := 1
+			q.Count = &one
+			q.Start = nil
+			jobs = append(jobs, job{v, q})
+		}
+	}
+
+	counts := make([]int, len(jobs))
+	errs := make([]error, len(jobs))
+	sem := make(chan struct{}, apiConcurrency)
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var resp api.GourmetResponse
+			errs[i] = c.Get("/gourmet/v1/", j.params, &resp)
+			counts[i] = resp.Results.ResultsAvailable
+		})
+	}
+	wg.Wait()
+
+	f := &Facet{Name: facet, Total: total, Counts: []Count{}}
+	for i, j := range jobs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("counting %s %s: %w", facet, j.value.Name, errs[i])
+		}
+		if counts[i] == 0 {
+			continue
+		}
+		c := Count{Code: j.value.Code, Name: j.value.Name, Count: counts[i]}
+		if facet == "amenity" {
+			c.Code = "" // amenities are named, like in Aggregate
+		}
+		f.Counts = append(f.Counts, c)
+	}
+	sortCounts(f.Counts)
+	return f, nil
+}
+
+// withValue narrows p to one facet value. It reports false when p already
+// filters the facet and code is not among its values.
+func withValue(p api.GourmetSearchParams, facet, code string) (api.GourmetSearchParams, bool) {
+	narrow := func(field *[]string) bool {
+		if len(*field) > 0 && !slices.Contains(*field, code) {
+			return false
+		}
+		*field = []string{code}
+		return true
+	}
+	var ok bool
+	switch facet {
+	case "genre":
+		ok = narrow(&p.Genre)
+	case "budget":
+		ok = narrow(&p.Budget)
+	case "middle_area":
+		ok = narrow(&p.MiddleArea)
+	case "small_area":
+		ok = narrow(&p.SmallArea)
+	case "amenity":
+		ok = p.SetAmenity(code)
+	}
+	return p, ok
+}
+
+func sortCounts(counts []Count) {
+	slices.SortFunc(counts, func(a, b Count) int {
+		return cmp.Or(b.Count-a.Count, cmp.Compare(a.Code, b.Code), cmp.Compare(a.Name, b.Name))
+	})
+}
@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/jackchuka/hpp/internal/api"
+)
+
+func TestAggregate(t *testing.T) {
+	shops := []api.Shop{
+		{Genre: api.CodeName{Code: "G001", Name: "居酒屋"}, StationName: "新橋", WiFi: "あり", Karaoke: "なし"},
+		{Genre: api.CodeName{Code: "G001", Name: "居酒屋"}, StationName: "浜松町", WiFi: "あり", Karaoke: "あり"},
+		{Genre: api.CodeName{Code: "G013", Name: "ラーメン"}, StationName: "新橋"},
+	}
+	facets := Aggregate(shops, []string{"genre", "station", "sub_genre", "amenity"})
+
+	want := []Count{{Code: "G001", Name: "居酒屋", Count: 2}, {Code: "G013", Name: "ラーメン", Count: 1}}
+	if !reflect.DeepEqual(facets[0].Counts, want) || facets[0].Total != 3 {
+		t.Fatalf("unexpected genre facet %+v", facets[0])
+	}
+	if c := facets[1].Counts; len(c) != 2 || c[0] != (Count{Name: "新橋", Count: 2}) {
+		t.Fatalf("unexpected station facet %+v", c)
+	}
+	if c := facets[2].Counts; c == nil || len(c) != 0 {
+		t.Fatalf("expected empty sub-genre facet, got %#v", c)
+	}
+	want = []Count{{Name: "wifi", Count: 2}, {Name: "karaoke", Count: 1}}
+	if !reflect.DeepEqual(facets[3].Counts, want) {
+		t.Fatalf("unexpected amenity facet %+v", facets[3].Counts)
+	}
+}
+
+func TestFromAPI(t *testing.T) {
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, q.Get("genre"))
+		mu.Unlock()
+		n := map[string]int{"G001": 40, "G002": 0, "G013": 7}[q.Get("genre")]
+		if q.Get("count") != "1" || q.Get("keyword") != "新橋" {
+			t.Errorf("unexpected query %v", q)
+		}
+		_, _ = fmt.Fprintf(w, `{"results":{"results_available":%d,"results_returned":"1","shop":[]}}`, n)
+	}))
+	defer srv.Close()
+	c := api.NewClient("k")
+	c.BaseURL = srv.URL
+
+	kw := "新橋"
+	genres := []api.CodeName{{Code: "G001", Name: "居酒屋"}, {Code: "G002", Name: "ダイニングバー"}, {Code: "G013", Name: "ラーメン"}}
+	f, err := FromAPI(c, api.GourmetSearchParams{Keyword: &kw}, "genre", 50, genres)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Count{{Code: "G001", Name: "居酒屋", Count: 40}, {Code: "G013", Name: "ラーメン", Count: 7}}
+	if !reflect.DeepEqual(f.Counts, want) || f.Total != 50 {
+		t.Fatalf("unexpected facet %+v", f)
+	}
+
+	// Values outside the search's own filter are not requested.
+	queries = nil
+	if _, err := FromAPI(c, api.GourmetSearchParams{Keyword: &kw, Genre: []string{"G013"}}, "genre", 7, genres); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(queries, []string{"G013"}) {
+		t.Fatalf("expected only G013 to be counted, got %v", queries)
+	}
+
+	if _, err := FromAPI(c, api.GourmetSearchParams{}, "station", 0, nil); err == nil {
+		t.Fatal("expected station to be rejected")
+	}
+}
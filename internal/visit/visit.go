@@ -0,0 +1,102 @@
+package visit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/hpp/internal/jsonfile"
+)
+
+// DateLayout is the format of visit dates.
+const DateLayout = time.DateOnly
+
+// Visit records that a shop was visited on a date (YYYY-MM-DD, local time).
+type Visit struct {
+	ID   string `json:"id"`
+	Date string `json:"date"`
+}
+
+// ParseWindow parses a look-back window such as "14d", "2w" or "36h".
+func ParseWindow(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			days, err := strconv.Atoi(n)
+			if err != nil || days <= 0 {
+				return 0, fmt.Errorf("invalid window %q (want e.g. 14d or 2w)", s)
+			}
+			return time.Duration(days) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q (want e.g. 14d or 2w)", s)
+	}
+	return d, nil
+}
+
+// Since returns the first date within window before now: with a window
+// of 14 days, visits on that date and the 13 days after count as recent.
+func Since(now time.Time, window time.Duration) string {
+	return now.Add(-window).Add(24 * time.Hour).Format(DateLayout)
+}
+
+// Store keeps visits in a JSON file, oldest first.
+type Store struct {
+	Path string
+}
+
+type visitFile struct {
+	Visits []Visit `json:"visits"`
+}
+
+func (s *Store) file() *jsonfile.File {
+	return &jsonfile.File{Path: s.Path, Name: "visits"}
+}
+
+// List returns all visits, oldest first.
+func (s *Store) List() ([]Visit, error) {
+	var f visitFile
+	if err := s.file().Read(&f); err != nil {
+		return nil, err
+	}
+	return f.Visits, nil
+}
+
+// Add records v. It reports false, changing nothing, when the visit was
+// already recorded.
+func (s *Store) Add(v Visit) (bool, error) {
+	if _, err := time.Parse(DateLayout, v.Date); err != nil {
+		return false, fmt.Errorf("invalid visit date %q (want YYYY-MM-DD)", v.Date)
+	}
+	var f visitFile
+	added := false
+	err := s.file().Update(&f, func() error {
+		if slices.Contains(f.Visits, v) {
+			return nil
+		}
+		f.Visits = append(f.Visits, v)
+		slices.SortStableFunc(f.Visits, func(a, b Visit) int { return strings.Compare(a.Date, b.Date) })
+		added = true
+		return nil
+	})
+	return added, err
+}
+
+// Recent returns the shops visited on or after the date since, mapped to
+// their last visit date.
+func (s *Store) Recent(since string) (map[string]string, error) {
+	list, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	recent := map[string]string{}
+	for _, v := range list {
+		if v.Date >= since && v.Date > recent[v.ID] {
+			recent[v.ID] = v.Date
+		}
+	}
+	return recent, nil
+}
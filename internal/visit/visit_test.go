@@ -0,0 +1,74 @@
+package visit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"14d", 14 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"36h", 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.in)
+		if err != nil || got != tt.want {
+			t.Fatalf("ParseWindow(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "d", "0d", "-3d", "two weeks"} {
+		if _, err := ParseWindow(bad); err == nil {
+			t.Fatalf("ParseWindow(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestSince(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	if got := Since(now, 14*24*time.Hour); got != "2026-10-06" {
+		t.Fatalf("Since = %s, want 2026-10-06", got)
+	}
+	if got := Since(now, 24*time.Hour); got != "2026-10-19" {
+		t.Fatalf("Since = %s, want today", got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	s := &Store{Path: filepath.Join(t.TempDir(), "hpp", "visits.json")}
+	for _, v := range []Visit{
+		{ID: "J1", Date: "2026-10-15"},
+		{ID: "J2", Date: "2026-09-01"},
+		{ID: "J1", Date: "2026-10-01"},
+	} {
+		if added, err := s.Add(v); err != nil || !added {
+			t.Fatalf("Add(%v) = %v, %v", v, added, err)
+		}
+	}
+	if added, err := s.Add(Visit{ID: "J1", Date: "2026-10-15"}); err != nil || added {
+		t.Fatalf("expected a repeated visit to be skipped, got %v, %v", added, err)
+	}
+	if _, err := s.Add(Visit{ID: "J3", Date: "10/19"}); err == nil {
+		t.Fatal("expected an error for a bad date")
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 3 || list[0].ID != "J2" || list[2].Date != "2026-10-15" {
+		t.Fatalf("expected visits sorted by date, got %+v", list)
+	}
+
+	recent, err := s.Recent("2026-10-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recent) != 1 || recent["J1"] != "2026-10-15" {
+		t.Fatalf("expected J1 last visited 2026-10-15, got %v", recent)
+	}
+}